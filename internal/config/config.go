@@ -9,6 +9,10 @@ import (
 type Config struct {
 	Dashboard DashboardConfig `mapstructure:"dashboard"`
 	Commits   CommitsConfig   `mapstructure:"commits"`
+	Tags      TagsConfig      `mapstructure:"tags"`
+	Checkout  CheckoutConfig  `mapstructure:"checkout"`
+	Cleanup   CleanupConfig   `mapstructure:"cleanup"`
+	Status    StatusConfig    `mapstructure:"status"`
 	Display   DisplayConfig   `mapstructure:"display"`
 }
 
@@ -21,6 +25,25 @@ type CommitsConfig struct {
 	ShowCount        int  `mapstructure:"show_count"`
 	ShowAuthor       bool `mapstructure:"show_author"`
 	ShowRelativeTime bool `mapstructure:"show_relative_time"`
+	Graph            bool `mapstructure:"graph"` // Start the commits panel in graph mode
+}
+
+type TagsConfig struct {
+	Sort string `mapstructure:"sort"` // "semver" or "date"
+}
+
+type CheckoutConfig struct {
+	StashUntracked bool `mapstructure:"stash_untracked"` // Auto-stash checkouts also carry untracked files
+}
+
+type CleanupConfig struct {
+	Base      string `mapstructure:"base"`       // Branch merged branches are checked against, the current one when empty
+	StaleDays int    `mapstructure:"stale_days"` // Branches without commits for this long are stale, 0 disables
+}
+
+type StatusConfig struct {
+	RenameThreshold int  `mapstructure:"rename_threshold"` // Similarity in percent for a rename, 0 disables detection
+	Copies          bool `mapstructure:"copies"`           // Also detect files copied from modified ones
 }
 
 type DisplayConfig struct {
@@ -35,6 +58,9 @@ func LoadConfig(path string) (*Config, error) {
 	v.SetDefault("dashboard.refresh_interval", "30s")
 	v.SetDefault("commits.show_count", 10)
 	v.SetDefault("commits.show_author", true)
+	v.SetDefault("tags.sort", "semver")
+	v.SetDefault("cleanup.stale_days", 90)
+	v.SetDefault("status.rename_threshold", 50)
 	v.SetDefault("display.colors", true)
 
 	// Config file
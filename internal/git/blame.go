@@ -0,0 +1,74 @@
+package git
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// BlameLine is a line of a file with the commit that last changed it
+type BlameLine struct {
+	Hash        string
+	Author      string
+	AuthorEmail string
+	When        time.Time
+	Text        string
+}
+
+// Blame annotates every line of a file at a revision
+type Blame struct {
+	Path   string
+	Rev    string // Hash of the blamed commit
+	Lines  []BlameLine
+	Oldest time.Time // Age range of the lines, for coloring
+	Newest time.Time
+}
+
+// GetBlame blames the file at path as of the given commit. Binary files
+// are refused, their "lines" mean nothing.
+func GetBlame(r *git.Repository, path string, from plumbing.Hash) (*Blame, error) {
+	c, err := r.CommitObject(from)
+	if err != nil {
+		return nil, err
+	}
+
+	blob, err := fileBlob(c, path)
+	if err != nil {
+		return nil, err
+	}
+	if blob.IsZero() {
+		return nil, fmt.Errorf("%s: no such file in %s", path, from.String()[:7])
+	}
+	content, err := blobContent(r, blob)
+	if err != nil {
+		return nil, err
+	}
+	if isBinary(content) {
+		return nil, fmt.Errorf("%s is a binary file", path)
+	}
+
+	result, err := git.Blame(c, path)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Blame{Path: path, Rev: from.String(), Lines: make([]BlameLine, 0, len(result.Lines))}
+	for _, l := range result.Lines {
+		b.Lines = append(b.Lines, BlameLine{
+			Hash:        l.Hash.String(),
+			Author:      l.AuthorName,
+			AuthorEmail: l.Author,
+			When:        l.Date,
+			Text:        l.Text,
+		})
+		if b.Oldest.IsZero() || l.Date.Before(b.Oldest) {
+			b.Oldest = l.Date
+		}
+		if l.Date.After(b.Newest) {
+			b.Newest = l.Date
+		}
+	}
+	return b, nil
+}
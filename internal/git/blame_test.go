@@ -0,0 +1,56 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetBlame(t *testing.T) {
+	r, dir := newTestRepo(t)
+	first := commitAt(t, r, dir, "a.txt", "one\ntwo\nthree\n", "first", 0)
+	second := commitAt(t, r, dir, "a.txt", "one\nTWO\nthree\nfour\n", "second", time.Hour)
+	commitAt(t, r, dir, "b.txt", "other\n", "unrelated", 2*time.Hour)
+	head := commitAt(t, r, dir, "bin.dat", "\x00\x01", "binary", 3*time.Hour)
+
+	b, err := GetBlame(r, "a.txt", head)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		text string
+		hash string
+	}{
+		{"one", first.String()},
+		{"TWO", second.String()},
+		{"three", first.String()},
+		{"four", second.String()},
+	}
+	if len(b.Lines) != len(want) {
+		t.Fatalf("got %d lines; want %d", len(b.Lines), len(want))
+	}
+	for i, w := range want {
+		if b.Lines[i].Text != w.text || b.Lines[i].Hash != w.hash {
+			t.Errorf("line %d = %q from %s; want %q from %s", i+1, b.Lines[i].Text, b.Lines[i].Hash[:7], w.text, w.hash[:7])
+		}
+	}
+	if !b.Oldest.Equal(testSignature.When) || !b.Newest.Equal(testSignature.When.Add(time.Hour)) {
+		t.Errorf("age range = %v .. %v", b.Oldest, b.Newest)
+	}
+
+	// Blaming an older revision only sees its own history
+	b, err = GetBlame(r, "a.txt", first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(b.Lines) != 3 || b.Lines[1].Text != "two" || b.Lines[1].Hash != first.String() {
+		t.Errorf("blame at first = %+v", b.Lines)
+	}
+
+	if _, err := GetBlame(r, "missing.txt", head); err == nil {
+		t.Error("blaming a missing file should fail")
+	}
+	if _, err := GetBlame(r, "bin.dat", head); err == nil {
+		t.Error("blaming a binary file should fail")
+	}
+}
@@ -1,27 +1,46 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 )
 
 type Branch struct {
-	Name       string
-	IsCurrent  bool
-	LastCommit time.Time
-	Hash       string
-	Remote     string
-	RemoteIdx  int // For handling multiple remotes if needed, simplified here
+	Name         string
+	IsCurrent    bool
+	LastCommit   time.Time
+	Hash         string
+	IsRemote     bool   // Remote-tracking branch (refs/remotes/...), Name is e.g. "origin/main"
+	Remote       string // Remote of the upstream (or of the branch itself if IsRemote), "." when tracking a local branch
+	RemoteIdx    int    // Position of Remote among the configured remotes, -1 if none
+	Upstream     string // Short name of the upstream, e.g. "origin/main"
+	UpstreamGone bool   // Upstream configured but its ref no longer exists
+	Ahead        int    // Commits on this branch missing from the upstream
+	Behind       int    // Commits on the upstream missing from this branch
+}
+
+// HasUpstream reports whether the branch tracks another branch
+func (b Branch) HasUpstream() bool {
+	return b.Upstream != ""
 }
 
 // GetBranches returns a list of local branches sorted by recency
 func GetBranches(r *git.Repository) ([]Branch, error) {
 	branches := []Branch{}
 
+	cfg, err := r.Config()
+	if err != nil {
+		return nil, err
+	}
+	remoteNames := sortedRemoteNames(cfg)
+
 	// Get HEAD to check current branch
 	headRef, err := r.Head()
 	currentBranchName := ""
@@ -45,15 +64,16 @@ func GetBranches(r *git.Repository) ([]Branch, error) {
 			lastCommit = commit.Author.When
 		}
 
-		// Basic remote tracking logic (simplified)
-		// To get real tracking info requires config parsing, for now just placeholder
-
-		branches = append(branches, Branch{
+		b := Branch{
 			Name:       name,
 			IsCurrent:  isCurrent,
 			LastCommit: lastCommit,
 			Hash:       ref.Hash().String(),
-		})
+			RemoteIdx:  -1,
+		}
+		fillUpstream(r, cfg, remoteNames, ref, &b)
+
+		branches = append(branches, b)
 		return nil
 	})
 	if err != nil {
@@ -68,6 +88,124 @@ func GetBranches(r *git.Repository) ([]Branch, error) {
 	return branches, nil
 }
 
+// GetRemoteBranches returns the remote-tracking branches grouped by remote
+// (in name order) and sorted by recency within each remote. Symbolic refs
+// such as origin/HEAD are skipped.
+func GetRemoteBranches(r *git.Repository) ([]Branch, error) {
+	branches := []Branch{}
+
+	cfg, err := r.Config()
+	if err != nil {
+		return nil, err
+	}
+	remoteNames := sortedRemoteNames(cfg)
+
+	refs, err := r.References()
+	if err != nil {
+		return nil, err
+	}
+
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if !ref.Name().IsRemote() || ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		var lastCommit time.Time
+		if commit, err := r.CommitObject(ref.Hash()); err == nil {
+			lastCommit = commit.Author.When
+		}
+
+		name := ref.Name().Short()
+		b := Branch{
+			Name:       name,
+			IsRemote:   true,
+			LastCommit: lastCommit,
+			Hash:       ref.Hash().String(),
+			RemoteIdx:  -1,
+		}
+
+		// Remote names may contain slashes, so prefer the longest configured match
+		for i, remote := range remoteNames {
+			if strings.HasPrefix(name, remote+"/") && len(remote) > len(b.Remote) {
+				b.Remote = remote
+				b.RemoteIdx = i
+			}
+		}
+		if b.Remote == "" {
+			b.Remote, _, _ = strings.Cut(name, "/")
+		}
+
+		branches = append(branches, b)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(branches, func(i, j int) bool {
+		if branches[i].Remote != branches[j].Remote {
+			return branches[i].Remote < branches[j].Remote
+		}
+		return branches[i].LastCommit.After(branches[j].LastCommit)
+	})
+
+	return branches, nil
+}
+
+func sortedRemoteNames(cfg *config.Config) []string {
+	names := make([]string, 0, len(cfg.Remotes))
+	for name := range cfg.Remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fillUpstream resolves branch.<name>.remote/merge to a remote-tracking ref
+// and counts how far the branch and its upstream have diverged
+func fillUpstream(r *git.Repository, cfg *config.Config, remoteNames []string, ref *plumbing.Reference, b *Branch) {
+	bc, ok := cfg.Branches[b.Name]
+	if !ok || bc.Remote == "" || bc.Merge == "" {
+		return
+	}
+
+	b.Remote = bc.Remote
+	b.RemoteIdx = sort.SearchStrings(remoteNames, bc.Remote)
+	if b.RemoteIdx >= len(remoteNames) || remoteNames[b.RemoteIdx] != bc.Remote {
+		b.RemoteIdx = -1
+	}
+
+	upstreamRef := upstreamRefName(cfg, bc)
+	b.Upstream = upstreamRef.Short()
+
+	upstream, err := r.Reference(upstreamRef, true)
+	if err != nil {
+		b.UpstreamGone = true
+		return
+	}
+
+	// A failed walk (e.g. missing objects) just leaves the counts at zero
+	b.Ahead, b.Behind, _ = aheadBehind(r, ref.Hash(), upstream.Hash())
+}
+
+// upstreamRefName maps the merge ref of a branch through its remote's fetch
+// refspecs, e.g. refs/heads/main on origin -> refs/remotes/origin/main
+func upstreamRefName(cfg *config.Config, bc *config.Branch) plumbing.ReferenceName {
+	if bc.Remote == "." {
+		return bc.Merge
+	}
+
+	if rc, ok := cfg.Remotes[bc.Remote]; ok {
+		for _, spec := range rc.Fetch {
+			if spec.Match(bc.Merge) {
+				return spec.Dst(bc.Merge)
+			}
+		}
+	}
+
+	return plumbing.NewRemoteReferenceName(bc.Remote, bc.Merge.Short())
+}
+
 // CheckoutBranch checks out the given branch name and waits for validation
 func CheckoutBranch(r *git.Repository, branchName string, force bool) error {
 	w, err := r.Worktree()
@@ -96,3 +234,259 @@ func CheckoutBranch(r *git.Repository, branchName string, force bool) error {
 
 	return fmt.Errorf("checkout verification failed: head still at %v", target) // Or current head name
 }
+
+// UnmergedError is returned by DeleteBranch when the branch holds commits
+// that no other ref reaches
+type UnmergedError struct {
+	Branch  string
+	Commits []Commit // Newest first
+}
+
+func (e *UnmergedError) Error() string {
+	return fmt.Sprintf("branch %s is not fully merged, %d commit(s) would become unreachable", e.Branch, len(e.Commits))
+}
+
+// validBranchName applies git's ref name rules to a new branch name
+func validBranchName(name string) error {
+	if name == "" || name == "HEAD" || plumbing.NewBranchReferenceName(name).Validate() != nil {
+		return fmt.Errorf("%q is not a valid branch name", name)
+	}
+	return nil
+}
+
+// CreateBranch creates a local branch at the commit rev resolves to, like
+// `git branch <name> <rev>`
+func CreateBranch(r *git.Repository, name, rev string) error {
+	if err := validBranchName(name); err != nil {
+		return err
+	}
+	refName := plumbing.NewBranchReferenceName(name)
+	if _, err := r.Reference(refName, false); err == nil {
+		return fmt.Errorf("a branch named %s already exists", name)
+	}
+
+	hash, err := ResolveRevision(r, rev)
+	if err != nil {
+		return err
+	}
+	if err := r.Storer.SetReference(plumbing.NewHashReference(refName, hash)); err != nil {
+		return err
+	}
+	return appendReflog(r, refName, ReflogEntry{
+		Old:       plumbing.ZeroHash,
+		New:       hash,
+		Committer: currentSignature(r),
+		Message:   "branch: Created from " + rev,
+	})
+}
+
+// RenameBranch renames a local branch together with its reflog and config,
+// following HEAD when it is the current branch, like `git branch -m`
+func RenameBranch(r *git.Repository, oldName, newName string) error {
+	if err := validBranchName(newName); err != nil {
+		return err
+	}
+	oldRef, err := r.Reference(plumbing.NewBranchReferenceName(oldName), false)
+	if err != nil {
+		return fmt.Errorf("branch %s: %w", oldName, err)
+	}
+	newRefName := plumbing.NewBranchReferenceName(newName)
+	if _, err := r.Reference(newRefName, false); err == nil {
+		return fmt.Errorf("a branch named %s already exists", newName)
+	}
+
+	logEntries, err := readReflog(r, oldRef.Name())
+	if err != nil {
+		return err
+	}
+
+	// The old ref goes first, "a" may be renamed to "a/b"
+	if err := writeReflog(r, oldRef.Name(), nil); err != nil {
+		return err
+	}
+	if err := r.Storer.RemoveReference(oldRef.Name()); err != nil {
+		return err
+	}
+	if err := r.Storer.SetReference(plumbing.NewHashReference(newRefName, oldRef.Hash())); err != nil {
+		// Put the branch back rather than lose it
+		_ = r.Storer.SetReference(oldRef)
+		return err
+	}
+	if err := writeReflog(r, newRefName, logEntries); err != nil {
+		return err
+	}
+	err = appendReflog(r, newRefName, ReflogEntry{
+		Old:       oldRef.Hash(),
+		New:       oldRef.Hash(),
+		Committer: currentSignature(r),
+		Message:   fmt.Sprintf("Branch: renamed %s to %s", oldRef.Name(), newRefName),
+	})
+	if err != nil {
+		return err
+	}
+
+	if head, err := r.Storer.Reference(plumbing.HEAD); err == nil && head.Target() == oldRef.Name() {
+		if err := r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, newRefName)); err != nil {
+			return err
+		}
+	}
+
+	cfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+	if bc, ok := cfg.Branches[oldName]; ok {
+		delete(cfg.Branches, oldName)
+		bc.Name = newName
+		cfg.Branches[newName] = bc
+		return r.SetConfig(cfg)
+	}
+	return nil
+}
+
+// UnreachableCommits lists the commits of a local branch that no other ref
+// (branch, remote-tracking branch, tag, detached HEAD or stash) reaches,
+// i.e. what deleting the branch would lose
+func UnreachableCommits(r *git.Repository, name string) ([]Commit, error) {
+	refName := plumbing.NewBranchReferenceName(name)
+	ref, err := r.Reference(refName, false)
+	if err != nil {
+		return nil, fmt.Errorf("branch %s: %w", name, err)
+	}
+
+	refs, err := r.References()
+	if err != nil {
+		return nil, err
+	}
+	var others []plumbing.Hash
+	err = refs.ForEach(func(other *plumbing.Reference) error {
+		if other.Name() == refName || other.Type() != plumbing.HashReference {
+			return nil
+		}
+		if c, err := peelCommit(r, other.Hash()); err == nil {
+			others = append(others, c.Hash)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	flags, err := paintCommits(r, ref.Hash(), others...)
+	if err != nil {
+		return nil, err
+	}
+	n := 0
+	for _, f := range flags {
+		if f == paintA {
+			n++
+		}
+	}
+	return commitsFlagged(r, ref.Hash(), flags, paintA, n)
+}
+
+// DeleteBranch deletes a local branch with its reflog and config. Unless
+// force is set, a branch holding commits nothing else reaches is kept and
+// an *UnmergedError lists them.
+func DeleteBranch(r *git.Repository, name string, force bool) error {
+	refName := plumbing.NewBranchReferenceName(name)
+	if head, err := r.Storer.Reference(plumbing.HEAD); err == nil && head.Target() == refName {
+		return fmt.Errorf("cannot delete %s, it is the current branch", name)
+	}
+	if _, err := r.Reference(refName, false); err != nil {
+		return fmt.Errorf("branch %s: %w", name, err)
+	}
+
+	if !force {
+		lost, err := UnreachableCommits(r, name)
+		if err != nil {
+			return err
+		}
+		if len(lost) > 0 {
+			return &UnmergedError{Branch: name, Commits: lost}
+		}
+	}
+
+	if err := r.Storer.RemoveReference(refName); err != nil {
+		return err
+	}
+	if err := writeReflog(r, refName, nil); err != nil {
+		return err
+	}
+
+	cfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Branches[name]; ok {
+		delete(cfg.Branches, name)
+		return r.SetConfig(cfg)
+	}
+	return nil
+}
+
+// SetUpstream makes a local branch track upstream, a remote-tracking branch
+// such as "origin/main" or another local branch, like `git branch -u`
+func SetUpstream(r *git.Repository, name, upstream string) error {
+	if _, err := r.Reference(plumbing.NewBranchReferenceName(name), false); err != nil {
+		return fmt.Errorf("branch %s: %w", name, err)
+	}
+	cfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+
+	bc := &config.Branch{Name: name}
+	if existing, ok := cfg.Branches[name]; ok {
+		bc = existing
+	}
+
+	switch {
+	case refExists(r, plumbing.ReferenceName("refs/remotes/"+upstream)):
+		// Remote names may contain slashes, so prefer the longest configured match
+		remote := ""
+		for _, rn := range sortedRemoteNames(cfg) {
+			if strings.HasPrefix(upstream, rn+"/") && len(rn) > len(remote) {
+				remote = rn
+			}
+		}
+		if remote == "" {
+			return fmt.Errorf("%s does not belong to a configured remote", upstream)
+		}
+		bc.Remote = remote
+		bc.Merge = plumbing.NewBranchReferenceName(strings.TrimPrefix(upstream, remote+"/"))
+	case refExists(r, plumbing.NewBranchReferenceName(upstream)):
+		if upstream == name {
+			return errors.New("a branch cannot track itself")
+		}
+		bc.Remote = "."
+		bc.Merge = plumbing.NewBranchReferenceName(upstream)
+	default:
+		return fmt.Errorf("no remote-tracking or local branch named %s", upstream)
+	}
+
+	cfg.Branches[name] = bc
+	return r.SetConfig(cfg)
+}
+
+// UnsetUpstream stops a local branch from tracking anything, like
+// `git branch --unset-upstream`
+func UnsetUpstream(r *git.Repository, name string) error {
+	cfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+	bc, ok := cfg.Branches[name]
+	if !ok || (bc.Remote == "" && bc.Merge == "") {
+		return fmt.Errorf("branch %s has no upstream", name)
+	}
+
+	bc.Remote = ""
+	bc.Merge = ""
+	return r.SetConfig(cfg)
+}
+
+func refExists(r *git.Repository, name plumbing.ReferenceName) bool {
+	_, err := r.Reference(name, false)
+	return err == nil
+}
@@ -0,0 +1,236 @@
+package git
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func findBranch(t *testing.T, branches []Branch, name string) Branch {
+	t.Helper()
+	for _, b := range branches {
+		if b.Name == name {
+			return b
+		}
+	}
+	t.Fatalf("branch %s not found in %+v", name, branches)
+	return Branch{}
+}
+
+func TestGetBranchesUpstream(t *testing.T) {
+	r, dir := newTestRepo(t)
+	base := commitFile(t, r, dir, "a.txt", "1\n", "one")
+
+	// origin/master stays at base plus one commit of its own
+	remoteOnly := commitFile(t, r, dir, "b.txt", "remote\n", "remote work")
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/remotes/origin/master", remoteOnly)); err != nil {
+		t.Fatal(err)
+	}
+
+	// master goes back to base and gains two local commits
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/heads/master", base)); err != nil {
+		t.Fatal(err)
+	}
+	w, _ := r.Worktree()
+	if err := w.Reset(&git.ResetOptions{Commit: base, Mode: git.HardReset}); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, r, dir, "c.txt", "local\n", "local one")
+	commitFile(t, r, dir, "d.txt", "local\n", "local two")
+
+	cfg, _ := r.Config()
+	cfg.Remotes["origin"] = &config.RemoteConfig{
+		Name:  "origin",
+		URLs:  []string{"https://example.com/repo.git"},
+		Fetch: []config.RefSpec{"+refs/heads/*:refs/remotes/origin/*"},
+	}
+	cfg.Branches["master"] = &config.Branch{Name: "master", Remote: "origin", Merge: "refs/heads/master"}
+	cfg.Branches["old"] = &config.Branch{Name: "old", Remote: "origin", Merge: "refs/heads/old"}
+	if err := r.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/heads/old", base)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/heads/plain", base)); err != nil {
+		t.Fatal(err)
+	}
+
+	branches, err := GetBranches(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	master := findBranch(t, branches, "master")
+	if master.Upstream != "origin/master" || master.Remote != "origin" || master.RemoteIdx != 0 {
+		t.Errorf("master upstream = %+v", master)
+	}
+	if master.Ahead != 2 || master.Behind != 1 {
+		t.Errorf("master ahead/behind = %d/%d; want 2/1", master.Ahead, master.Behind)
+	}
+
+	old := findBranch(t, branches, "old")
+	if !old.UpstreamGone || old.Upstream != "origin/old" {
+		t.Errorf("old = %+v; want a gone upstream", old)
+	}
+
+	plain := findBranch(t, branches, "plain")
+	if plain.HasUpstream() || plain.RemoteIdx != -1 {
+		t.Errorf("plain = %+v; want no upstream", plain)
+	}
+}
+
+func TestGetRemoteBranches(t *testing.T) {
+	r, dir := newTestRepo(t)
+	older := commitFile(t, r, dir, "a.txt", "1\n", "one")
+	newer := commitFile(t, r, dir, "a.txt", "2\n", "two")
+
+	refs := map[string]plumbing.Hash{
+		"refs/remotes/origin/old":      older,
+		"refs/remotes/origin/new":      newer,
+		"refs/remotes/upstream/master": older,
+	}
+	for name, h := range refs {
+		if err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(name), h)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	head := plumbing.NewSymbolicReference("refs/remotes/origin/HEAD", "refs/remotes/origin/new")
+	if err := r.Storer.SetReference(head); err != nil {
+		t.Fatal(err)
+	}
+
+	branches, err := GetRemoteBranches(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, b := range branches {
+		if !b.IsRemote {
+			t.Errorf("%s is not marked remote", b.Name)
+		}
+		names = append(names, b.Remote+":"+b.Name)
+	}
+	want := "origin:origin/new origin:origin/old upstream:upstream/master"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("GetRemoteBranches() = %s; want %s", got, want)
+	}
+
+	// Inspection works on remote refs without a local branch
+	h, err := ResolveRevision(r, "origin/old")
+	if err != nil || h != older {
+		t.Errorf("ResolveRevision(origin/old) = %s, %v; want %s", h, err, older)
+	}
+	commits, err := GetRecentCommits(r, h, 10)
+	if err != nil || len(commits) != 1 {
+		t.Errorf("GetRecentCommits(origin/old) = %d commits, %v; want 1", len(commits), err)
+	}
+}
+
+func TestBranchManagement(t *testing.T) {
+	r, dir := newTestRepo(t)
+	base := commitFile(t, r, dir, "a.txt", "1\n", "one")
+
+	if err := CreateBranch(r, "feature", "HEAD"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CreateBranch(r, "feature", "HEAD"); err == nil {
+		t.Error("creating an existing branch should fail")
+	}
+	if err := CreateBranch(r, "bad..name", "HEAD"); err == nil {
+		t.Error("an invalid name should be refused")
+	}
+
+	// A branch whose commits are reachable elsewhere deletes without asking
+	if err := DeleteBranch(r, "feature", false); err != nil {
+		t.Fatalf("deleting a merged branch: %v", err)
+	}
+	if refExists(r, "refs/heads/feature") {
+		t.Error("feature still exists")
+	}
+
+	own := commitFiles(t, r, map[string]string{"a.txt": "2\n"}, "feature work", time.Minute, base)
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/heads/feature", own)); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, _ := r.Config()
+	cfg.Remotes["origin"] = &config.RemoteConfig{
+		Name:  "origin",
+		URLs:  []string{"https://example.com/repo.git"},
+		Fetch: []config.RefSpec{"+refs/heads/*:refs/remotes/origin/*"},
+	}
+	if err := r.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/remotes/origin/main", base)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := SetUpstream(r, "feature", "origin/main"); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetUpstream(r, "feature", "origin/missing"); err == nil {
+		t.Error("tracking a missing branch should fail")
+	}
+
+	// Renaming carries the upstream and the reflog along
+	if err := RenameBranch(r, "feature", "feature/x"); err != nil {
+		t.Fatal(err)
+	}
+	branches, _ := GetBranches(r)
+	b := findBranch(t, branches, "feature/x")
+	if b.Hash != own.String() || b.Upstream != "origin/main" {
+		t.Errorf("renamed branch = %+v; want %s tracking origin/main", b, own)
+	}
+	logEntries, _ := readReflog(r, "refs/heads/feature/x")
+	if len(logEntries) == 0 || !strings.HasPrefix(logEntries[0].Message, "Branch: renamed") {
+		t.Errorf("reflog = %+v; want the rename on top", logEntries)
+	}
+
+	if err := SetUpstream(r, "feature/x", "master"); err != nil {
+		t.Fatal(err)
+	}
+	branches, _ = GetBranches(r)
+	if b := findBranch(t, branches, "feature/x"); b.Upstream != "master" || b.Remote != "." || b.Ahead != 1 {
+		t.Errorf("upstream = %+v; want local master, 1 ahead", b)
+	}
+	if err := UnsetUpstream(r, "feature/x"); err != nil {
+		t.Fatal(err)
+	}
+	branches, _ = GetBranches(r)
+	if b := findBranch(t, branches, "feature/x"); b.HasUpstream() {
+		t.Errorf("upstream still set: %+v", b)
+	}
+
+	// Its own commit would be lost, so it needs force
+	err := DeleteBranch(r, "feature/x", false)
+	var unmerged *UnmergedError
+	if !errors.As(err, &unmerged) || len(unmerged.Commits) != 1 || unmerged.Commits[0].Hash != own.String() {
+		t.Fatalf("err = %v; want an UnmergedError listing %s", err, own)
+	}
+	if err := DeleteBranch(r, "feature/x", true); err != nil {
+		t.Fatal(err)
+	}
+	if refExists(r, "refs/heads/feature/x") {
+		t.Error("feature/x still exists after a forced delete")
+	}
+
+	// The current branch can be renamed but not deleted
+	if err := DeleteBranch(r, "master", true); err == nil {
+		t.Error("deleting the current branch should fail")
+	}
+	if err := RenameBranch(r, "master", "main"); err != nil {
+		t.Fatal(err)
+	}
+	head, _ := r.Head()
+	if head.Name() != "refs/heads/main" || head.Hash() != base {
+		t.Errorf("HEAD = %s %s; want main at %s", head.Name(), head.Hash(), base)
+	}
+}
@@ -0,0 +1,312 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ErrWouldOverwrite is returned by Checkout when local changes are in the way
+var ErrWouldOverwrite = errors.New("local changes would be overwritten by checkout")
+
+// CheckoutPlan is what switching to a branch would do to the worktree
+type CheckoutPlan struct {
+	Branch      string
+	Target      plumbing.Hash
+	Changes     []FileChange // Paths that differ between HEAD and the branch
+	Overwritten []string     // Modified or staged files among Changes
+	Untracked   []string     // Untracked files the branch would replace
+}
+
+// Blocked reports whether the checkout would lose local work
+func (p *CheckoutPlan) Blocked() bool {
+	return len(p.Overwritten) > 0 || len(p.Untracked) > 0
+}
+
+// PlanCheckout works out what checking out a local branch would change
+// without touching anything. Like `git checkout`, local changes to files
+// that are the same on both branches are not in the way, they carry over.
+func PlanCheckout(r *git.Repository, branch string) (*CheckoutPlan, error) {
+	ref, err := r.Reference(plumbing.NewBranchReferenceName(branch), true)
+	if err != nil {
+		return nil, fmt.Errorf("branch %s: %w", branch, err)
+	}
+	plan := &CheckoutPlan{Branch: branch, Target: ref.Hash()}
+
+	headHash := plumbing.ZeroHash // Unborn branch
+	if head, err := r.Head(); err == nil {
+		headHash = head.Hash()
+	}
+	headTree, err := commitTree(r, headHash)
+	if err != nil {
+		return nil, err
+	}
+	targetTree, err := commitTree(r, plan.Target)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Changes, err = treeChanges(headTree, targetTree); err != nil {
+		return nil, err
+	}
+
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range idx.Entries {
+		if e.Stage != stageMerged {
+			return nil, fmt.Errorf("cannot switch branches with unmerged path %s", e.Name)
+		}
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	// Local changes under a path are in the way too: a file may turn into a
+	// directory of the same name, or the other way round
+	under := make(map[string][]string)
+	for name := range status {
+		for dir := parentDir(name); dir != ""; dir = parentDir(dir) {
+			under[dir] = append(under[dir], name)
+		}
+	}
+	seen := make(map[string]bool)
+	for _, ch := range plan.Changes {
+		paths := []string{ch.Path}
+		for dir := parentDir(ch.Path); dir != ""; dir = parentDir(dir) {
+			paths = append(paths, dir)
+		}
+		paths = append(paths, under[ch.Path]...)
+
+		for _, name := range paths {
+			s, dirty := status[name]
+			switch {
+			case !dirty || seen[name]:
+			case s.Staging == git.Untracked && s.Worktree == git.Untracked:
+				plan.Untracked = append(plan.Untracked, name)
+				seen[name] = true
+			case s.Staging != git.Unmodified || s.Worktree != git.Unmodified:
+				plan.Overwritten = append(plan.Overwritten, name)
+				seen[name] = true
+			}
+		}
+	}
+	sort.Strings(plan.Overwritten)
+	sort.Strings(plan.Untracked)
+
+	return plan, nil
+}
+
+// Checkout switches to a local branch the way `git checkout` does: only
+// the files that differ between HEAD and the branch are rewritten, every
+// other local change is kept. When local changes are in the way nothing is
+// touched and the plan is returned with ErrWouldOverwrite.
+func Checkout(r *git.Repository, branch string) (*CheckoutPlan, error) {
+	plan, err := PlanCheckout(r, branch)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Blocked() {
+		return plan, ErrWouldOverwrite
+	}
+
+	from := "(unborn)"
+	oldHash := plumbing.ZeroHash
+	if head, err := r.Head(); err == nil {
+		oldHash = head.Hash()
+		from = oldHash.String()
+		if head.Name().IsBranch() {
+			from = head.Name().Short()
+		}
+	}
+
+	targetTree, err := commitTree(r, plan.Target)
+	if err != nil {
+		return nil, err
+	}
+	w, err := r.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkWritable(w, plan.Changes); err != nil {
+		return plan, err
+	}
+
+	// Deletions first, a file may be replaced by a directory of the same name
+	for _, ch := range plan.Changes {
+		if ch.Status == "D" {
+			removeIndexEntry(idx, ch.Path)
+			if err := removeWorktreeFile(w, ch.Path); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, ch := range plan.Changes {
+		if ch.Status == "D" {
+			continue
+		}
+		f, ok := treeEntry(targetTree, ch.Path)
+		if !ok {
+			continue
+		}
+		content, err := blobContent(r, f.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeWorktreeFile(w, ch.Path, content, f.Mode); err != nil {
+			return nil, err
+		}
+		setIndexEntry(idx, ch.Path, f.Hash, f.Mode, len(content))
+	}
+	if err := r.Storer.SetIndex(idx); err != nil {
+		return nil, err
+	}
+
+	target := plumbing.NewBranchReferenceName(branch)
+	if err := r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, target)); err != nil {
+		return nil, err
+	}
+	err = appendReflog(r, plumbing.HEAD, ReflogEntry{
+		Old:       oldHash,
+		New:       plan.Target,
+		Committer: currentSignature(r),
+		Message:   fmt.Sprintf("checkout: moving from %s to %s", from, branch),
+	})
+	return plan, err
+}
+
+// checkWritable makes sure every file of a checkout can be written once
+// the deletions are done, before anything is touched. Ignored files, which
+// the plan does not see, can still be in the way of a path.
+func checkWritable(w *git.Worktree, changes []FileChange) error {
+	deleted := make(map[string]bool)
+	for _, ch := range changes {
+		if ch.Status == "D" {
+			deleted[ch.Path] = true
+		}
+	}
+
+	fs := w.Filesystem
+	for _, ch := range changes {
+		if ch.Status == "D" {
+			continue
+		}
+		for dir := parentDir(ch.Path); dir != ""; dir = parentDir(dir) {
+			if fi, err := fs.Lstat(dir); err == nil && !fi.IsDir() && !deleted[dir] {
+				return fmt.Errorf("cannot write %s, the file %s is in the way", ch.Path, dir)
+			}
+		}
+		if fi, err := fs.Lstat(ch.Path); err == nil && fi.IsDir() && !clearedByDeletes(fs, ch.Path, deleted) {
+			return fmt.Errorf("cannot write %s, a directory with other files is in the way", ch.Path)
+		}
+	}
+	return nil
+}
+
+// clearedByDeletes reports whether every file under dir is deleted, so
+// the directory goes away with them
+func clearedByDeletes(fs billy.Filesystem, dir string, deleted map[string]bool) bool {
+	entries, err := fs.ReadDir(dir)
+	if err != nil || len(entries) == 0 {
+		return false
+	}
+	for _, e := range entries {
+		name := path.Join(dir, e.Name())
+		if e.IsDir() {
+			if !clearedByDeletes(fs, name, deleted) {
+				return false
+			}
+		} else if !deleted[name] {
+			return false
+		}
+	}
+	return true
+}
+
+// StashCheckoutResult is what an auto-stash checkout did
+type StashCheckoutResult struct {
+	Plan      *CheckoutPlan
+	Stashed   bool     // Local changes were stashed and reapplied
+	Conflicts []string // Paths the stash conflicts in; it is kept as stash@{0}
+}
+
+// StashCheckout switches to a local branch carrying uncommitted work
+// across: the changes are stashed, the branch is checked out and the stash
+// is reapplied. When reapplying conflicts nothing is written, the entry is
+// kept and the conflicting paths are returned. Untracked files are stashed
+// only with includeUntracked; if one is in the way without it, nothing is
+// touched and ErrWouldOverwrite is returned. Once planned, the result is
+// returned with any error, holding the plan that refused the checkout.
+func StashCheckout(r *git.Repository, branch string, includeUntracked bool) (*StashCheckoutResult, error) {
+	plan, err := PlanCheckout(r, branch)
+	if err != nil {
+		return nil, err
+	}
+	result := &StashCheckoutResult{Plan: plan}
+	if len(plan.Untracked) > 0 && !includeUntracked {
+		return result, ErrWouldOverwrite
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range status {
+		untracked := s.Staging == git.Untracked && s.Worktree == git.Untracked
+		if !untracked || includeUntracked {
+			result.Stashed = true
+			break
+		}
+	}
+
+	var stash *StashEntry
+	if result.Stashed {
+		if stash, err = CreateStash(r, "autostash before checkout of "+branch, includeUntracked); err != nil {
+			return nil, err
+		}
+	}
+	if plan, err := Checkout(r, branch); err != nil {
+		if plan != nil {
+			result.Plan = plan
+		}
+		if result.Stashed {
+			if _, popErr := PopStash(r, 0, stash.Hash); popErr != nil {
+				return result, fmt.Errorf("%v, and restoring your changes failed: %w (they are in stash@{0})", err, popErr)
+			}
+		}
+		return result, err
+	}
+	if !result.Stashed {
+		return result, nil
+	}
+
+	applied, err := ApplyStash(r, 0, stash.Hash)
+	if err != nil {
+		return nil, fmt.Errorf("switched to %s, but reapplying stash@{0} failed: %w", branch, err)
+	}
+	if len(applied.Conflicts) > 0 {
+		result.Conflicts = applied.Conflicts
+		return result, nil
+	}
+	return result, DropStash(r, 0, stash.Hash)
+}
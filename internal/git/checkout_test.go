@@ -0,0 +1,220 @@
+package git
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestCheckout(t *testing.T) {
+	r, dir := newTestRepo(t)
+	commitFile(t, r, dir, "shared.txt", "s\n", "shared")
+	main := commitFile(t, r, dir, "a.txt", "1\n", "a")
+	other := commitFiles(t, r, map[string]string{"shared.txt": "s\n", "a.txt": "2\n", "new.txt": "n\n"}, "other", 0, main)
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/heads/other", other)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A change to a file both branches agree on carries over
+	writeFile(t, dir, "shared.txt", "local\n")
+	plan, err := Checkout(r, "other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Changes) != 2 {
+		t.Errorf("changes = %+v; want a.txt and new.txt", plan.Changes)
+	}
+	head, _ := r.Head()
+	if head.Name() != "refs/heads/other" || head.Hash() != other {
+		t.Errorf("HEAD = %s %s; want other", head.Name(), head.Hash())
+	}
+	if got := readTestFile(t, dir, "a.txt"); got != "2\n" {
+		t.Errorf("a.txt = %q; want the branch version", got)
+	}
+	if got := readTestFile(t, dir, "shared.txt"); got != "local\n" {
+		t.Errorf("shared.txt = %q; local change lost", got)
+	}
+	w, _ := r.Worktree()
+	status, _ := w.Status()
+	if len(status) != 1 || status["shared.txt"] == nil {
+		t.Errorf("status after checkout = %v; want only shared.txt modified", status)
+	}
+
+	// Switching back removes the branch's file
+	if _, err := Checkout(r, "master"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "new.txt")); !os.IsNotExist(err) {
+		t.Errorf("new.txt still exists after switching back: %v", err)
+	}
+
+	// A modified file that differs between the branches blocks the switch
+	writeFile(t, dir, "a.txt", "mine\n")
+	writeFile(t, dir, "new.txt", "untracked\n")
+	plan, err = Checkout(r, "other")
+	if !errors.Is(err, ErrWouldOverwrite) {
+		t.Fatalf("err = %v; want ErrWouldOverwrite", err)
+	}
+	if len(plan.Overwritten) != 1 || plan.Overwritten[0] != "a.txt" || len(plan.Untracked) != 1 || plan.Untracked[0] != "new.txt" {
+		t.Errorf("plan = %+v; want a.txt overwritten and new.txt untracked", plan)
+	}
+	head, _ = r.Head()
+	if head.Name() != "refs/heads/master" || readTestFile(t, dir, "a.txt") != "mine\n" {
+		t.Error("a blocked checkout changed something")
+	}
+
+	if _, err := Checkout(r, "missing"); err == nil {
+		t.Error("checking out a missing branch should fail")
+	}
+}
+
+func TestCheckoutFileDirectoryClash(t *testing.T) {
+	r, dir := newTestRepo(t)
+	main := commitFile(t, r, dir, "a.txt", "a\n", "a")
+	other := commitFiles(t, r, map[string]string{"a.txt": "a\n", "x/y.txt": "y\n", "f": "f\n"}, "other", 0, main)
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/heads/other", other)); err != nil {
+		t.Fatal(err)
+	}
+	unchanged := func() {
+		t.Helper()
+		head, _ := r.Head()
+		if head.Name() != "refs/heads/master" {
+			t.Errorf("HEAD moved to %s", head.Name())
+		}
+		if _, err := os.Stat(filepath.Join(dir, "x", "y.txt")); err == nil {
+			t.Error("x/y.txt was written by a refused checkout")
+		}
+	}
+
+	// An untracked file where the branch has a directory, and an untracked
+	// directory where it has a file
+	writeFile(t, dir, "x", "mine\n")
+	writeFile(t, dir, "f/inner.txt", "mine\n")
+	plan, err := Checkout(r, "other")
+	if !errors.Is(err, ErrWouldOverwrite) {
+		t.Fatalf("err = %v; want ErrWouldOverwrite", err)
+	}
+	if len(plan.Untracked) != 2 || plan.Untracked[0] != "f/inner.txt" || plan.Untracked[1] != "x" {
+		t.Errorf("untracked = %v; want f/inner.txt and x", plan.Untracked)
+	}
+	unchanged()
+
+	// Ignored files are not in the plan, the checkout still stops before writing
+	if err := os.Remove(filepath.Join(dir, "x")); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, ".git/info/exclude", "f/\n")
+	if _, err := Checkout(r, "other"); err == nil || errors.Is(err, ErrWouldOverwrite) {
+		t.Fatalf("err = %v; want the directory in the way of f", err)
+	}
+	unchanged()
+
+	if err := os.RemoveAll(filepath.Join(dir, "f")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Checkout(r, "other"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, dir, "f"); got != "f\n" {
+		t.Errorf("f = %q after checkout", got)
+	}
+}
+
+func TestStashCheckout(t *testing.T) {
+	r, dir := newTestRepo(t)
+	main := commitFile(t, r, dir, "a.txt", "1\n2\n3\n", "a")
+	other := commitFiles(t, r, map[string]string{"a.txt": "1\n2\n3\n4\n", "new.txt": "n\n"}, "other", 0, main)
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/heads/other", other)); err != nil {
+		t.Fatal(err)
+	}
+
+	// An untracked file in the way is only stashed when asked to
+	writeFile(t, dir, "new.txt", "untracked\n")
+	if _, err := StashCheckout(r, "other", false); !errors.Is(err, ErrWouldOverwrite) {
+		t.Fatalf("err = %v; want ErrWouldOverwrite", err)
+	}
+	if stashes, _ := GetStashList(r); len(stashes) != 0 {
+		t.Fatalf("a refused checkout left %d stashes", len(stashes))
+	}
+	os.Remove(filepath.Join(dir, "new.txt"))
+
+	// A change that merges with the branch is carried over
+	writeFile(t, dir, "a.txt", "0\n1\n2\n3\n")
+	result, err := StashCheckout(r, "other", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Stashed || len(result.Conflicts) != 0 {
+		t.Errorf("result = %+v; want stashed without conflicts", result)
+	}
+	if got := readTestFile(t, dir, "a.txt"); got != "0\n1\n2\n3\n4\n" {
+		t.Errorf("a.txt = %q; want both changes", got)
+	}
+	if stashes, _ := GetStashList(r); len(stashes) != 0 {
+		t.Errorf("the stash was not dropped after a clean reapply: %v", stashes)
+	}
+
+	// A conflicting change keeps the stash
+	writeFile(t, dir, "a.txt", "1\n2\n3\n4\n")
+	if _, err := Checkout(r, "master"); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "a.txt", "1\n2\nX\n")
+	result, err = StashCheckout(r, "other", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "a.txt" {
+		t.Errorf("conflicts = %v; want a.txt", result.Conflicts)
+	}
+	head, _ := r.Head()
+	if head.Name() != "refs/heads/other" {
+		t.Errorf("HEAD = %s; want other even when the stash conflicts", head.Name())
+	}
+	if got := readTestFile(t, dir, "a.txt"); got != "1\n2\n3\n4\n" {
+		t.Errorf("a.txt = %q; want the branch version", got)
+	}
+	if stashes, _ := GetStashList(r); len(stashes) != 1 {
+		t.Errorf("got %d stashes; want the conflicting one kept", len(stashes))
+	}
+}
+
+func TestStashCheckoutStillBlocked(t *testing.T) {
+	r, dir := newTestRepo(t)
+	main := commitFile(t, r, dir, "a.txt", "1\n", "a")
+	other := commitFiles(t, r, map[string]string{"a.txt": "2\n"}, "other", 0, main)
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/heads/other", other)); err != nil {
+		t.Fatal(err)
+	}
+
+	// A staged deletion with a new file at the same path is still in the way
+	// once stashed; the changes come back and the refusing plan is returned
+	if err := os.Remove(filepath.Join(dir, "a.txt")); err != nil {
+		t.Fatal(err)
+	}
+	if err := StagePaths(r, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "a.txt", "recreated\n")
+
+	result, err := StashCheckout(r, "other", true)
+	if !errors.Is(err, ErrWouldOverwrite) {
+		t.Fatalf("err = %v; want ErrWouldOverwrite", err)
+	}
+	if result == nil || result.Plan == nil || !result.Plan.Blocked() {
+		t.Fatalf("result = %+v; want the blocked plan", result)
+	}
+	head, _ := r.Head()
+	if head.Name() != "refs/heads/master" {
+		t.Errorf("HEAD = %s; want master after a refused checkout", head.Name())
+	}
+	if got := readTestFile(t, dir, "a.txt"); got != "recreated\n" {
+		t.Errorf("a.txt = %q; want the local file back", got)
+	}
+	if stashes, _ := GetStashList(r); len(stashes) != 0 {
+		t.Errorf("got %d stashes; want the autostash popped", len(stashes))
+	}
+}
@@ -0,0 +1,179 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// cleanupLogPath is where batch deletions are recorded, relative to .git
+const cleanupLogPath = "gitdash-cleanup.log"
+
+// CleanupCandidate is a local branch that looks safe to delete
+type CleanupCandidate struct {
+	Branch
+	Author string // Author of the tip commit
+	Merged bool   // Tip is reachable from the base branch
+	Stale  bool   // No commit within the stale period
+	Gone   bool   // Upstream configured but deleted on the remote
+}
+
+// DeletedBranch is what is needed to bring a deleted branch back
+type DeletedBranch struct {
+	Name   string
+	Hash   plumbing.Hash
+	Remote string // Upstream config, empty when the branch tracked nothing
+	Merge  plumbing.ReferenceName
+}
+
+// FindCleanupCandidates lists the local branches that are merged into base,
+// have no commit newer than staleAfter (zero disables the check) or lost
+// their upstream, oldest first. An empty base means the current branch. The
+// current branch and base itself are never candidates.
+func FindCleanupCandidates(r *git.Repository, base string, staleAfter time.Duration, now time.Time) ([]CleanupCandidate, error) {
+	if base == "" {
+		head, err := r.Head()
+		if err != nil {
+			return nil, err
+		}
+		base = head.Name().Short()
+	}
+	baseHash, err := ResolveRevision(r, base)
+	if err != nil {
+		return nil, fmt.Errorf("cleanup base %s: %w", base, err)
+	}
+
+	branches, err := GetBranches(r)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := []CleanupCandidate{}
+	for _, b := range branches {
+		if b.IsCurrent || b.Name == base {
+			continue
+		}
+		c := CleanupCandidate{Branch: b, Gone: b.UpstreamGone}
+		c.Stale = staleAfter > 0 && now.Sub(b.LastCommit) > staleAfter
+
+		tip := plumbing.NewHash(b.Hash)
+		ahead, _, err := aheadBehind(r, tip, baseHash)
+		if err != nil {
+			return nil, err
+		}
+		c.Merged = ahead == 0
+
+		if !c.Merged && !c.Stale && !c.Gone {
+			continue
+		}
+		if commit, err := r.CommitObject(tip); err == nil {
+			c.Author = commit.Author.Name
+		}
+		candidates = append(candidates, c)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].LastCommit.Before(candidates[j].LastCommit)
+	})
+	return candidates, nil
+}
+
+// DeleteBranches force-deletes local branches and records them in
+// .git/gitdash-cleanup.log as `git branch` commands, so a batch can be
+// undone even after gitdash exits. It stops at the first failure and
+// returns what was deleted until then.
+func DeleteBranches(r *git.Repository, names []string) ([]DeletedBranch, error) {
+	cfg, err := r.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []DeletedBranch
+	var deleteErr error
+	for _, name := range names {
+		ref, err := r.Reference(plumbing.NewBranchReferenceName(name), false)
+		if err != nil {
+			deleteErr = fmt.Errorf("branch %s: %w", name, err)
+			break
+		}
+		d := DeletedBranch{Name: name, Hash: ref.Hash()}
+		if bc, ok := cfg.Branches[name]; ok {
+			d.Remote = bc.Remote
+			d.Merge = bc.Merge
+		}
+		if err := DeleteBranch(r, name, true); err != nil {
+			deleteErr = err
+			break
+		}
+		deleted = append(deleted, d)
+	}
+
+	if len(deleted) > 0 {
+		if err := recordCleanup(r, deleted); err != nil && deleteErr == nil {
+			deleteErr = err
+		}
+	}
+	return deleted, deleteErr
+}
+
+// recordCleanup appends a batch to the cleanup log
+func recordCleanup(r *git.Repository, deleted []DeletedBranch) error {
+	fs, err := gitDirFS(r)
+	if err != nil {
+		return err
+	}
+
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("# %s deleted %d branch(es), run these lines to restore them\n", time.Now().Format(time.RFC3339), len(deleted)))
+	for _, d := range deleted {
+		s.WriteString(fmt.Sprintf("git branch %s %s\n", d.Name, d.Hash))
+	}
+
+	f, err := fs.OpenFile(cleanupLogPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write([]byte(s.String())); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// RestoreBranches recreates deleted branches with their upstream. Branches
+// that were created again in the meantime are left alone.
+func RestoreBranches(r *git.Repository, deleted []DeletedBranch) error {
+	cfg, err := r.Config()
+	if err != nil {
+		return err
+	}
+
+	for _, d := range deleted {
+		refName := plumbing.NewBranchReferenceName(d.Name)
+		if refExists(r, refName) {
+			continue
+		}
+		if err := r.Storer.SetReference(plumbing.NewHashReference(refName, d.Hash)); err != nil {
+			return err
+		}
+		err := appendReflog(r, refName, ReflogEntry{
+			Old:       plumbing.ZeroHash,
+			New:       d.Hash,
+			Committer: currentSignature(r),
+			Message:   "branch: Restored after cleanup",
+		})
+		if err != nil {
+			return err
+		}
+		if d.Remote != "" {
+			cfg.Branches[d.Name] = &config.Branch{Name: d.Name, Remote: d.Remote, Merge: d.Merge}
+		}
+	}
+	return r.SetConfig(cfg)
+}
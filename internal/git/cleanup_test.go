@@ -0,0 +1,94 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestCleanup(t *testing.T) {
+	r, dir := newTestRepo(t)
+	base := commitFile(t, r, dir, "a.txt", "1\n", "base")
+	tip := commitFiles(t, r, map[string]string{"a.txt": "2\n"}, "main work", time.Minute, base)
+	active := commitFiles(t, r, map[string]string{"a.txt": "3\n"}, "active work", 2*time.Minute, tip)
+	old := commitFiles(t, r, map[string]string{"a.txt": "old\n"}, "old work", -60*24*time.Hour, base)
+	refs := map[string]plumbing.Hash{
+		"refs/heads/master": tip,
+		"refs/heads/merged": base,
+		"refs/heads/active": active,
+		"refs/heads/old":    old,
+		"refs/heads/gone":   active,
+	}
+	for name, h := range refs {
+		if err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(name), h)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cfg, _ := r.Config()
+	cfg.Remotes["origin"] = &config.RemoteConfig{
+		Name:  "origin",
+		URLs:  []string{"https://example.com/repo.git"},
+		Fetch: []config.RefSpec{"+refs/heads/*:refs/remotes/origin/*"},
+	}
+	cfg.Branches["gone"] = &config.Branch{Name: "gone", Remote: "origin", Merge: "refs/heads/gone"}
+	if err := r.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	now := testSignature.When.Add(10 * 24 * time.Hour)
+	candidates, err := FindCleanupCandidates(r, "", 30*24*time.Hour, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]CleanupCandidate{}
+	for _, c := range candidates {
+		got[c.Name] = c
+	}
+	if len(candidates) != 3 || candidates[0].Name != "old" {
+		t.Fatalf("candidates = %+v; want old first, then merged and gone", candidates)
+	}
+	if c := got["old"]; !c.Stale || c.Merged || c.Gone || c.Author != "Test" {
+		t.Errorf("old = %+v; want stale only", c)
+	}
+	if c := got["merged"]; !c.Merged || c.Stale {
+		t.Errorf("merged = %+v; want merged only", c)
+	}
+	if c := got["gone"]; !c.Gone || c.Merged {
+		t.Errorf("gone = %+v; want a gone upstream only", c)
+	}
+
+	if _, err := FindCleanupCandidates(r, "missing", 0, now); err == nil {
+		t.Error("a missing base should fail")
+	}
+
+	deleted, err := DeleteBranches(r, []string{"merged", "gone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 2 || refExists(r, "refs/heads/merged") || refExists(r, "refs/heads/gone") {
+		t.Fatalf("deleted = %+v; want both branches gone", deleted)
+	}
+	record, err := os.ReadFile(filepath.Join(dir, ".git", cleanupLogPath))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(record), "git branch merged "+base.String()) || !strings.Contains(string(record), "git branch gone "+active.String()) {
+		t.Errorf("undo record = %q", record)
+	}
+
+	if err := RestoreBranches(r, deleted); err != nil {
+		t.Fatal(err)
+	}
+	branches, _ := GetBranches(r)
+	if b := findBranch(t, branches, "gone"); b.Hash != active.String() || b.Upstream != "origin/gone" {
+		t.Errorf("restored gone = %+v; want its tip and upstream back", b)
+	}
+	if b := findBranch(t, branches, "merged"); b.Hash != base.String() {
+		t.Errorf("restored merged = %+v", b)
+	}
+}
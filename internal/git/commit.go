@@ -0,0 +1,215 @@
+package git
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Identity returns user.name and user.email from the repository config,
+// falling back to the global one for what it leaves unset. A global config
+// that cannot be loaded counts as empty. ok is false when either is
+// missing, git refuses to commit without them.
+func Identity(r *git.Repository) (sig object.Signature, ok bool) {
+	if cfg, err := r.Config(); err == nil {
+		sig.Name, sig.Email = cfg.User.Name, cfg.User.Email
+	}
+	if sig.Name == "" || sig.Email == "" {
+		if global, err := config.LoadConfig(config.GlobalScope); err == nil {
+			sig.Name = cmp.Or(sig.Name, global.User.Name)
+			sig.Email = cmp.Or(sig.Email, global.User.Email)
+		}
+	}
+	return sig, sig.Name != "" && sig.Email != ""
+}
+
+// HeadMessage returns the full message of the HEAD commit, the starting
+// point when amending
+func HeadMessage(r *git.Repository) (string, error) {
+	head, err := r.Head()
+	if err != nil {
+		return "", err
+	}
+	c, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return "", err
+	}
+	return c.Message, nil
+}
+
+// PendingMessage returns the message git prepared for the commit that
+// finishes a merge, cherry-pick or revert, without its comment lines, or ""
+func PendingMessage(r *git.Repository) string {
+	fs, err := gitDirFS(r)
+	if err != nil {
+		return ""
+	}
+	var lines []string
+	for _, l := range strings.Split(stateFile(fs, "MERGE_MSG"), "\n") {
+		if !strings.HasPrefix(l, "#") {
+			lines = append(lines, l)
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// mergeHeads reads the commits being merged from MERGE_HEAD
+func mergeHeads(r *git.Repository) ([]plumbing.Hash, error) {
+	fs, err := gitDirFS(r)
+	if err != nil {
+		return nil, err
+	}
+	var heads []plumbing.Hash
+	for _, l := range strings.Fields(stateFile(fs, "MERGE_HEAD")) {
+		if !plumbing.IsHash(l) {
+			return nil, fmt.Errorf("invalid MERGE_HEAD entry %q", l)
+		}
+		heads = append(heads, plumbing.NewHash(l))
+	}
+	return heads, nil
+}
+
+// removeStateFiles deletes state files from the git directory, ignoring missing ones
+func removeStateFiles(r *git.Repository, names ...string) error {
+	fs, err := gitDirFS(r)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if err := fs.Remove(name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// cleanMessage tidies a commit message like git's default cleanup for
+// messages given on the command line: trailing whitespace is stripped,
+// leading and trailing blank lines dropped and runs of blank lines merged
+func cleanMessage(msg string) string {
+	var lines []string
+	blank := false
+	for _, l := range strings.Split(msg, "\n") {
+		l = strings.TrimRight(l, " \t\r")
+		if l == "" {
+			blank = len(lines) > 0
+			continue
+		}
+		if blank {
+			lines = append(lines, "")
+			blank = false
+		}
+		lines = append(lines, l)
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// CreateCommit commits the index on HEAD through the go-git worktree and
+// records it in the reflogs. With amend the HEAD commit is replaced
+// instead, keeping its author and parents as `git commit --amend` does.
+// During a merge the commit gets the MERGE_HEAD commits as extra parents,
+// and like git the merge, cherry-pick and revert state is cleared after.
+func CreateCommit(r *git.Repository, message string, amend bool) (plumbing.Hash, error) {
+	message = cleanMessage(message)
+	if message == "" {
+		return plumbing.ZeroHash, errors.New("aborting commit due to empty commit message")
+	}
+	sig, ok := Identity(r)
+	if !ok {
+		return plumbing.ZeroHash, errors.New("user.name and user.email are not set, configure them with git config --global")
+	}
+	sig.When = time.Now()
+
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	for _, e := range idx.Entries {
+		if e.Stage != stageMerged {
+			return plumbing.ZeroHash, fmt.Errorf("cannot commit with unresolved conflicts in %s", e.Name)
+		}
+	}
+
+	oldHash := plumbing.ZeroHash
+	head, err := r.Head()
+	if err == nil {
+		oldHash = head.Hash()
+	} else if err != plumbing.ErrReferenceNotFound {
+		return plumbing.ZeroHash, err
+	}
+
+	heads, err := mergeHeads(r)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	opts := &git.CommitOptions{Author: &sig, Committer: &sig}
+	action := "commit"
+	switch {
+	case len(heads) > 0 && amend:
+		return plumbing.ZeroHash, errors.New("cannot amend while a merge is in progress, commit the merge first")
+	case len(heads) > 0:
+		opts.Parents = append([]plumbing.Hash{oldHash}, heads...)
+		opts.AllowEmptyCommits = true
+		action = "commit (merge)"
+	case amend:
+		if oldHash.IsZero() {
+			return plumbing.ZeroHash, errors.New("nothing to amend, the branch has no commits yet")
+		}
+		old, err := r.CommitObject(oldHash)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		author := old.Author
+		opts.Author = &author
+		// go-git's Amend keeps only the first parent, a merge passes them all
+		if old.NumParents() > 1 {
+			opts.Parents = old.ParentHashes
+		} else {
+			opts.Amend = true
+		}
+		action = "commit (amend)"
+	case oldHash.IsZero():
+		action = "commit (initial)"
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	hash, err := w.Commit(message, opts)
+	if errors.Is(err, git.ErrEmptyCommit) {
+		return plumbing.ZeroHash, errors.New("nothing staged to commit")
+	}
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	if err := removeStateFiles(r, "MERGE_HEAD", "MERGE_MSG", "MERGE_MODE", "AUTO_MERGE", "SQUASH_MSG", "CHERRY_PICK_HEAD", "REVERT_HEAD"); err != nil {
+		return hash, err
+	}
+
+	entry := ReflogEntry{
+		Old:       oldHash,
+		New:       hash,
+		Committer: sig,
+		Message:   action + ": " + commitSubject(message),
+	}
+	if ref, err := r.Reference(plumbing.HEAD, false); err == nil && ref.Type() == plumbing.SymbolicReference {
+		if err := appendReflog(r, ref.Target(), entry); err != nil {
+			return hash, err
+		}
+	}
+	return hash, appendReflog(r, plumbing.HEAD, entry)
+}
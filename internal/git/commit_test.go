@@ -0,0 +1,224 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestCreateCommit(t *testing.T) {
+	r, dir := newTestRepo(t)
+	cfg, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.User.Name, cfg.User.Email = "Ada", "ada@example.com"
+	if err := r.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := CreateCommit(r, "nothing", false); err == nil {
+		t.Error("committing an empty index succeeded")
+	}
+
+	writeFile(t, dir, "a.txt", "a\n")
+	if err := StagePaths(r); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateCommit(r, " \n\n  ", false); err == nil {
+		t.Error("committing with a blank message succeeded")
+	}
+	first, err := CreateCommit(r, "\nAdd a  \n\n\n\nWith a body\n\n", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := r.CommitObject(first)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Message != "Add a\n\nWith a body\n" || c.Author.Name != "Ada" || c.NumParents() != 0 {
+		t.Errorf("commit = %q by %s with %d parents", c.Message, c.Author.Name, c.NumParents())
+	}
+
+	if _, err := CreateCommit(r, "again", false); err == nil {
+		t.Error("committing without staged changes succeeded")
+	}
+
+	// A merge commit made elsewhere, then amended with a new file and message
+	other := commitFile(t, r, dir, "b.txt", "b\n", "b")
+	tree, err := r.CommitObject(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	merge, err := writeCommit(r, tree.TreeHash, []plumbing.Hash{other, first}, testSignature, "Merge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Storer.SetReference(plumbing.NewHashReference(head.Name(), merge)); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, dir, "c.txt", "c\n")
+	if err := StagePaths(r, "c.txt"); err != nil {
+		t.Fatal(err)
+	}
+	amended, err := CreateCommit(r, "Merge, with c", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err = r.CommitObject(amended)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Message != "Merge, with c\n" || c.Author.Name != testSignature.Name || c.Committer.Name != "Ada" {
+		t.Errorf("amended = %q, author %s, committer %s", c.Message, c.Author.Name, c.Committer.Name)
+	}
+	if c.NumParents() != 2 || c.ParentHashes[0] != other || c.ParentHashes[1] != first {
+		t.Errorf("amended parents = %v; want %s %s", c.ParentHashes, other, first)
+	}
+	if _, err := c.File("c.txt"); err != nil {
+		t.Errorf("amended commit misses c.txt: %v", err)
+	}
+	if msg, err := HeadMessage(r); err != nil || msg != "Merge, with c\n" {
+		t.Errorf("HeadMessage = %q, %v", msg, err)
+	}
+
+	entries, err := readReflog(r, plumbing.HEAD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var messages []string
+	for _, e := range entries {
+		messages = append(messages, e.Message)
+	}
+	if got, want := strings.Join(messages, " | "), "commit (amend): Merge, with c | commit (initial): Add a"; got != want {
+		t.Errorf("HEAD reflog = %q; want %q", got, want)
+	}
+	if entries, err := readReflog(r, head.Name()); err != nil || len(entries) != 2 || entries[0].Old != merge || entries[0].New != amended {
+		t.Errorf("branch reflog = %+v, %v", entries, err)
+	}
+}
+
+func TestMergeCommit(t *testing.T) {
+	r, dir := newTestRepo(t)
+	cfg, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.User.Name, cfg.User.Email = "Ada", "ada@example.com"
+	if err := r.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	base := commitFile(t, r, dir, "a.txt", "a\n", "a")
+	head := commitFile(t, r, dir, "b.txt", "b\n", "b")
+	if PendingMessage(r) != "" {
+		t.Error("pending message outside a merge")
+	}
+
+	// A merge of base that stopped for a conflict, resolved and staged
+	writeFile(t, dir, ".git/MERGE_HEAD", base.String()+"\n")
+	writeFile(t, dir, ".git/MERGE_MSG", "Merge branch 'main'\n\n# Conflicts:\n#\ta.txt\n")
+	writeFile(t, dir, ".git/MERGE_MODE", "")
+	writeFile(t, dir, "a.txt", "resolved\n")
+	if err := StagePaths(r, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if msg := PendingMessage(r); msg != "Merge branch 'main'" {
+		t.Errorf("pending message = %q", msg)
+	}
+	if _, err := CreateCommit(r, "Merge branch 'main'", true); err == nil {
+		t.Error("amending during a merge succeeded")
+	}
+
+	merge, err := CreateCommit(r, "Merge branch 'main'", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := r.CommitObject(merge)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.ParentHashes) != 2 || c.ParentHashes[0] != head || c.ParentHashes[1] != base {
+		t.Errorf("merge parents = %v; want %s %s", c.ParentHashes, head, base)
+	}
+	for _, name := range []string{"MERGE_HEAD", "MERGE_MSG", "MERGE_MODE"} {
+		if _, err := os.Stat(filepath.Join(dir, ".git", name)); !os.IsNotExist(err) {
+			t.Errorf("%s left after the merge commit: %v", name, err)
+		}
+	}
+	entries, err := readReflog(r, plumbing.HEAD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) == 0 || entries[0].Message != "commit (merge): Merge branch 'main'" {
+		t.Errorf("HEAD reflog = %+v", entries)
+	}
+
+	// A merge that brings in nothing new still records both parents
+	writeFile(t, dir, ".git/MERGE_HEAD", base.String()+"\n")
+	if _, err := CreateCommit(r, "Merge again", false); err != nil {
+		t.Errorf("empty merge commit: %v", err)
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	r, dir := newTestRepo(t)
+
+	// No home directory, so no global config can be loaded at all
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	if _, ok := Identity(r); ok {
+		t.Error("identity found without any config")
+	}
+
+	cfg, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.User.Email = "ada@example.com"
+	if err := r.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := Identity(r); ok {
+		t.Error("identity found without user.name")
+	}
+	cfg.User.Name = "Ada"
+	if err := r.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if sig, ok := Identity(r); !ok || sig.Name != "Ada" || sig.Email != "ada@example.com" {
+		t.Errorf("local identity = %s <%s>, %v", sig.Name, sig.Email, ok)
+	}
+	writeFile(t, dir, "b.txt", "b\n")
+	if err := StagePaths(r); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateCommit(r, "b", false); err != nil {
+		t.Errorf("commit with a local identity only: %v", err)
+	}
+
+	// The global config fills in what the repository leaves unset
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	writeFile(t, home, ".gitconfig", "[user]\n\tname = Global\n\temail = global@example.com\n")
+	r, _ = newTestRepo(t)
+	cfg, err = r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.User.Email = "ada@example.com"
+	if err := r.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if sig, ok := Identity(r); !ok || sig.Name != "Global" || sig.Email != "ada@example.com" {
+		t.Errorf("merged identity = %s <%s>, %v", sig.Name, sig.Email, ok)
+	}
+}
@@ -1,12 +1,13 @@
 package git
 
 import (
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/plumbing/storer"
 )
 
 type Commit struct {
@@ -15,48 +16,188 @@ type Commit struct {
 	Author      string
 	AuthorEmail string
 	When        time.Time
+	Parents     []string
+	Refs        []Decoration // Refs pointing at the commit; only filled by GetCommitGraph
 }
 
-// GetRecentCommits returns the last n commits from the given branch (or HEAD if empty)
-func GetRecentCommits(r *git.Repository, branchName string, n int) ([]Commit, error) {
-	var hash plumbing.Hash
+func newCommit(c *object.Commit) Commit {
+	parents := make([]string, len(c.ParentHashes))
+	for i, p := range c.ParentHashes {
+		parents[i] = p.String()
+	}
+	return Commit{
+		Hash:        c.Hash.String(),
+		Message:     c.Message,
+		Author:      c.Author.Name,
+		AuthorEmail: c.Author.Email,
+		When:        c.Author.When,
+		Parents:     parents,
+	}
+}
 
-	if branchName == "" {
-		ref, err := r.Head()
-		if err != nil {
-			return nil, nil // Empty repo or other error, return empty list
+// GetRecentCommits returns the last n commits reachable from the given commit.
+// A zero hash (e.g. an unborn branch) yields no commits.
+func GetRecentCommits(r *git.Repository, from plumbing.Hash, n int) ([]Commit, error) {
+	return QueryCommits(r, from, CommitQuery{Limit: n})
+}
+
+// CommitQuery filters a history walk. Zero fields match every commit.
+type CommitQuery struct {
+	Author       *regexp.Regexp // Matched against "Name <email>" of the author
+	Message      string         // Case-insensitive substring of the message
+	MessageRegex *regexp.Regexp
+	Path         string    // Only commits that changed this file or directory
+	Since        time.Time // Committer date bounds, inclusive, as in `git log --since`
+	Until        time.Time
+	Limit        int // Stop after this many matches, 0 for no limit
+}
+
+// QueryCommits collects the commits matching q, newest first
+func QueryCommits(r *git.Repository, from plumbing.Hash, q CommitQuery) ([]Commit, error) {
+	var commits []Commit
+	err := WalkCommits(r, from, q, func(c Commit) bool {
+		commits = append(commits, c)
+		return true
+	})
+	return commits, err
+}
+
+// WalkCommits streams the commits matching q to fn, newest committed first.
+// The walk ends when fn returns false, q.Limit matches were found, or every
+// remaining commit is older than q.Since, so a narrow query over a huge
+// history only reads what it needs.
+func WalkCommits(r *git.Repository, from plumbing.Hash, q CommitQuery, fn func(Commit) bool) error {
+	if from.IsZero() {
+		return nil
+	}
+
+	message := strings.ToLower(q.Message)
+	path := strings.Trim(q.Path, "/")
+	found := 0
+
+	return walkByDate(r, from, func(c *object.Commit) (bool, error) {
+		if !q.Since.IsZero() && c.Committer.When.Before(q.Since) {
+			return false, nil // Everything still queued is older
+		}
+		if !q.Until.IsZero() && c.Committer.When.After(q.Until) {
+			return true, nil
+		}
+		if q.Author != nil && !q.Author.MatchString(c.Author.Name+" <"+c.Author.Email+">") {
+			return true, nil
+		}
+		if message != "" && !strings.Contains(strings.ToLower(c.Message), message) {
+			return true, nil
+		}
+		if q.MessageRegex != nil && !q.MessageRegex.MatchString(c.Message) {
+			return true, nil
+		}
+		if path != "" {
+			touched, err := touchesPath(r, c, path)
+			if err != nil {
+				return false, err
+			}
+			if !touched {
+				return true, nil
+			}
+		}
+
+		found++
+		if !fn(newCommit(c)) {
+			return false, nil
+		}
+		return q.Limit <= 0 || found < q.Limit, nil
+	})
+}
+
+// touchesPath reports whether c changed path (a file or directory). Like
+// `git log -- path`, a merge only counts when it differs from every parent.
+func touchesPath(r *git.Repository, c *object.Commit, path string) (bool, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return false, err
+	}
+	own := pathHash(tree, path)
+
+	if c.NumParents() == 0 {
+		return !own.IsZero(), nil
+	}
+
+	for _, p := range c.ParentHashes {
+		parentTree, err := commitTree(r, p)
+		if err == plumbing.ErrObjectNotFound {
+			continue // Shallow boundary, nothing to compare with
 		}
-		hash = ref.Hash()
-	} else {
-		ref, err := r.Reference(plumbing.ReferenceName("refs/heads/"+branchName), true)
 		if err != nil {
-			return nil, err
+			return false, err
+		}
+		if pathHash(parentTree, path) == own {
+			return false, nil
 		}
-		hash = ref.Hash()
 	}
+	return true, nil
+}
+
+// pathHash is the blob or tree hash at path, zero when it does not exist.
+// Equal hashes mean nothing under path changed.
+func pathHash(tree *object.Tree, path string) plumbing.Hash {
+	entry, err := tree.FindEntry(path)
+	if err != nil {
+		return plumbing.ZeroHash
+	}
+	return entry.Hash
+}
 
-	cIter, err := r.Log(&git.LogOptions{From: hash})
+// CommitDetail is everything shown when a single commit is opened
+type CommitDetail struct {
+	Hash           string
+	Message        string
+	Author         string
+	AuthorEmail    string
+	AuthorWhen     time.Time
+	Committer      string
+	CommitterEmail string
+	CommitterWhen  time.Time
+	Parents        []string
+	Files          []FileStat // Changes against the first parent (or an empty tree for a root commit)
+	Patch          *object.Patch
+}
+
+// GetCommitDetail loads the commit with the given hash and diffs it against its first parent
+func GetCommitDetail(r *git.Repository, hash string) (*CommitDetail, error) {
+	c, err := r.CommitObject(plumbing.NewHash(hash))
 	if err != nil {
 		return nil, err
 	}
 
-	var commits []Commit
-	count := 0
-	err = cIter.ForEach(func(c *object.Commit) error {
-		commits = append(commits, Commit{
-			Hash:        c.Hash.String(),
-			Message:     c.Message,
-			Author:      c.Author.Name,
-			AuthorEmail: c.Author.Email,
-			When:        c.Author.When,
-		})
-		count++
-
-		if count >= n {
-			return storer.ErrStop
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+	parentTree := &object.Tree{}
+	if c.NumParents() > 0 {
+		if parentTree, err = commitTree(r, c.ParentHashes[0]); err != nil {
+			return nil, err
 		}
-		return nil
-	})
+	}
+
+	d := &CommitDetail{
+		Hash:           c.Hash.String(),
+		Message:        c.Message,
+		Author:         c.Author.Name,
+		AuthorEmail:    c.Author.Email,
+		AuthorWhen:     c.Author.When,
+		Committer:      c.Committer.Name,
+		CommitterEmail: c.Committer.Email,
+		CommitterWhen:  c.Committer.When,
+		Parents:        []string{},
+	}
+	for _, p := range c.ParentHashes {
+		d.Parents = append(d.Parents, p.String())
+	}
+
+	if d.Files, d.Patch, err = diffStat(parentTree, tree); err != nil {
+		return nil, err
+	}
 
-	return commits, nil
+	return d, nil
 }
@@ -0,0 +1,162 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func TestGetCommitDetail(t *testing.T) {
+	r, dir := newTestRepo(t)
+	root := commitFile(t, r, dir, "a.txt", "one\ntwo\nthree\n", "root")
+
+	d, err := GetCommitDetail(r, root.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Parents) != 0 || len(d.Files) != 1 {
+		t.Fatalf("root detail = %+v", d)
+	}
+	if f := d.Files[0]; f.Status != "A" || f.Additions != 3 || f.Deletions != 0 {
+		t.Errorf("root file = %+v; want A +3 -0", f)
+	}
+
+	// Modify a.txt, move a larger file and add a binary one in one commit
+	body := "line 1\nline 2\nline 3\nline 4\nline 5\nline 6\nline 7\nline 8\n"
+	commitFile(t, r, dir, "old.txt", body, "add old")
+	w, _ := r.Worktree()
+	writeFile(t, dir, "a.txt", "one\n2\nthree\nfour")
+	if _, err := w.Move("old.txt", "new.txt"); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "img.bin", "\x00\x01\x02")
+	if _, err := w.Add("."); err != nil {
+		t.Fatal(err)
+	}
+	author := testSignature
+	committer := testSignature
+	committer.Name = "Committer"
+	committer.When = author.When.Add(time.Hour)
+	hash, err := w.Commit("change things\n\nwith a body\n", &git.CommitOptions{Author: &author, Committer: &committer})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err = GetCommitDetail(r, hash.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Message != "change things\n\nwith a body\n" || d.Committer != "Committer" || !d.CommitterWhen.After(d.AuthorWhen) {
+		t.Errorf("detail header = %+v", d)
+	}
+	if len(d.Parents) != 1 {
+		t.Errorf("parents = %v; want 1", d.Parents)
+	}
+
+	files := map[string]FileStat{}
+	for _, f := range d.Files {
+		files[f.Path] = f
+	}
+	if f := files["a.txt"]; f.Status != "M" || f.Additions != 2 || f.Deletions != 1 {
+		t.Errorf("a.txt = %+v; want M +2 -1", f)
+	}
+	if f := files["new.txt"]; f.Status != "R" || f.OldPath != "old.txt" || f.Additions != 0 || f.Deletions != 0 {
+		t.Errorf("new.txt = %+v; want a pure rename of old.txt", f)
+	}
+	if f := files["img.bin"]; f.Status != "A" || !f.Binary {
+		t.Errorf("img.bin = %+v; want a binary addition", f)
+	}
+	if len(d.Files) != 3 {
+		t.Errorf("got %d files; want 3", len(d.Files))
+	}
+}
+
+func TestQueryCommits(t *testing.T) {
+	r, dir := newTestRepo(t)
+
+	// One commit per day, alternating authors and directories
+	type spec struct{ author, email, file, msg string }
+	specs := []spec{
+		{"Priya", "priya@example.com", "internal/billing/a.go", "billing: add invoices"},
+		{"Sam", "sam@example.com", "internal/auth/b.go", "auth: login"},
+		{"Priya", "priya@example.com", "internal/auth/c.go", "auth: fix token refresh"},
+		{"Sam", "sam@example.com", "internal/billing/a.go", "billing: fix rounding"},
+		{"Priya", "priya@example.com", "internal/billing/d.go", "Billing: Refunds"},
+	}
+	w, _ := r.Worktree()
+	var hashes []string
+	for i, s := range specs {
+		writeFile(t, dir, s.file, s.msg+"\n")
+		if _, err := w.Add(s.file); err != nil {
+			t.Fatal(err)
+		}
+		sig := testSignature
+		sig.Name, sig.Email = s.author, s.email
+		sig.When = sig.When.AddDate(0, 0, i)
+		h, err := w.Commit(s.msg, &git.CommitOptions{Author: &sig, Committer: &sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, h.String())
+	}
+	head, _ := r.Head()
+	day := func(i int) time.Time { return testSignature.When.AddDate(0, 0, i) }
+
+	tests := []struct {
+		name string
+		q    CommitQuery
+		want []int // Indexes into specs, newest first
+	}{
+		{"everything", CommitQuery{}, []int{4, 3, 2, 1, 0}},
+		{"limit", CommitQuery{Limit: 2}, []int{4, 3}},
+		// The limit counts matches, not commits walked past
+		{"limit with a filter", CommitQuery{Path: "internal/auth", Limit: 1}, []int{2}},
+		{"limit past old matches", CommitQuery{Message: "login", Limit: 1}, []int{1}},
+		{"author by name", CommitQuery{Author: regexp.MustCompile("(?i)priya")}, []int{4, 2, 0}},
+		{"author by email", CommitQuery{Author: regexp.MustCompile("sam@")}, []int{3, 1}},
+		{"message substring", CommitQuery{Message: "billing"}, []int{4, 3, 0}},
+		{"message regex", CommitQuery{MessageRegex: regexp.MustCompile(`^auth: `)}, []int{2, 1}},
+		{"path prefix", CommitQuery{Path: "internal/billing/"}, []int{4, 3, 0}},
+		{"single file", CommitQuery{Path: "internal/billing/a.go"}, []int{3, 0}},
+		{"missing path", CommitQuery{Path: "internal/bill"}, nil},
+		{"since", CommitQuery{Since: day(3)}, []int{4, 3}},
+		{"until", CommitQuery{Until: day(1)}, []int{1, 0}},
+		{
+			"combined",
+			CommitQuery{Author: regexp.MustCompile("(?i)priya"), Path: "internal/billing", Since: day(1)},
+			[]int{4},
+		},
+	}
+
+	for _, tt := range tests {
+		commits, err := QueryCommits(r, head.Hash(), tt.q)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		var got []int
+		for _, c := range commits {
+			for i, h := range hashes {
+				if c.Hash == h {
+					got = append(got, i)
+				}
+			}
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: got commits %v; want %v", tt.name, got, tt.want)
+		}
+	}
+
+	// Streaming stops as soon as the callback says so
+	calls := 0
+	err := WalkCommits(r, head.Hash(), CommitQuery{}, func(Commit) bool {
+		calls++
+		return false
+	})
+	if err != nil || calls != 1 {
+		t.Errorf("WalkCommits early stop: %d calls, %v", calls, err)
+	}
+}
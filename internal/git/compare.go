@@ -0,0 +1,90 @@
+package git
+
+import (
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Comparison describes how two revisions diverged
+type Comparison struct {
+	MergeBase string   // Empty when the histories are unrelated
+	OnlyA     []Commit // Reachable from A but not B (`git log B..A`), newest first
+	OnlyB     []Commit // Reachable from B but not A (`git log A..B`), newest first
+	Files     []FileStat
+	Patch     *object.Patch
+}
+
+// CompareCommits finds the merge-base of a and b and the commits unique to
+// each side. The diffstat is what merging b into a would bring in: the
+// changes from the merge-base to b, like `git diff a...b` or a pull request.
+// Unrelated histories are diffed tree to tree.
+func CompareCommits(r *git.Repository, a, b plumbing.Hash) (*Comparison, error) {
+	ca, err := r.CommitObject(a)
+	if err != nil {
+		return nil, err
+	}
+	cb, err := r.CommitObject(b)
+	if err != nil {
+		return nil, err
+	}
+
+	cmp := &Comparison{}
+	from := a
+	bases, err := ca.MergeBase(cb)
+	if err != nil {
+		return nil, err
+	}
+	if len(bases) > 0 {
+		cmp.MergeBase = bases[0].Hash.String()
+		from = bases[0].Hash
+	}
+
+	if a != b {
+		flags, err := paintCommits(r, a, b)
+		if err != nil {
+			return nil, err
+		}
+
+		counts := map[uint8]int{}
+		for _, f := range flags {
+			counts[f]++
+		}
+		if cmp.OnlyA, err = commitsFlagged(r, a, flags, paintA, counts[paintA]); err != nil {
+			return nil, err
+		}
+		if cmp.OnlyB, err = commitsFlagged(r, b, flags, paintB, counts[paintB]); err != nil {
+			return nil, err
+		}
+	}
+
+	fromTree, err := commitTree(r, from)
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := cb.Tree()
+	if err != nil {
+		return nil, err
+	}
+	if cmp.Files, cmp.Patch, err = diffStat(fromTree, toTree); err != nil {
+		return nil, err
+	}
+
+	return cmp, nil
+}
+
+// commitsFlagged lists the n commits painted exactly flag, in `git log`
+// order from the side they were painted from
+func commitsFlagged(r *git.Repository, from plumbing.Hash, flags map[plumbing.Hash]uint8, flag uint8, n int) ([]Commit, error) {
+	commits := []Commit{}
+	if n == 0 {
+		return commits, nil
+	}
+	err := walkByDate(r, from, func(c *object.Commit) (bool, error) {
+		if flags[c.Hash] == flag {
+			commits = append(commits, newCommit(c))
+		}
+		return len(commits) < n, nil
+	})
+	return commits, err
+}
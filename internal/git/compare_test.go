@@ -0,0 +1,99 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+)
+
+// commitFiles stores a commit holding exactly the given files, without touching the worktree
+func commitFiles(t *testing.T, r *git.Repository, files map[string]string, msg string, offset time.Duration, parents ...plumbing.Hash) plumbing.Hash {
+	t.Helper()
+	flat := map[string]treeFile{}
+	for name, content := range files {
+		blob, err := writeBlob(r, []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		flat[name] = treeFile{Hash: blob, Mode: filemode.Regular}
+	}
+	tree, err := writeTree(r, flat)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := testSignature
+	sig.When = sig.When.Add(offset)
+	h, err := writeCommit(r, tree, parents, sig, msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestCompareCommits(t *testing.T) {
+	r, _ := newTestRepo(t)
+	base := commitFiles(t, r, map[string]string{"a.txt": "base\n"}, "base", 0)
+	a1 := commitFiles(t, r, map[string]string{"a.txt": "base\nmain\n"}, "main 1", time.Minute, base)
+	a2 := commitFiles(t, r, map[string]string{"a.txt": "base\nmain\n", "m.txt": "m\n"}, "main 2", 2*time.Minute, a1)
+	b1 := commitFiles(t, r, map[string]string{"a.txt": "base\n", "b.txt": "one\ntwo\n"}, "feature 1", 3*time.Minute, base)
+
+	cmp, err := CompareCommits(r, a2, b1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp.MergeBase != base.String() {
+		t.Errorf("merge-base = %s; want base", cmp.MergeBase)
+	}
+	hashes := func(commits []Commit) []string {
+		var out []string
+		for _, c := range commits {
+			out = append(out, c.Hash)
+		}
+		return out
+	}
+	if got := hashes(cmp.OnlyA); len(got) != 2 || got[0] != a2.String() || got[1] != a1.String() {
+		t.Errorf("only in A = %v; want main 2, main 1", got)
+	}
+	if got := hashes(cmp.OnlyB); len(got) != 1 || got[0] != b1.String() {
+		t.Errorf("only in B = %v; want feature 1", got)
+	}
+
+	// The diffstat is what B changed since the merge-base, not A's work undone
+	files := map[string]FileStat{}
+	for _, f := range cmp.Files {
+		files[f.Path] = f
+	}
+	if len(cmp.Files) != 1 || files["b.txt"].Status != "A" || files["b.txt"].Additions != 2 {
+		t.Errorf("files = %+v; want only b.txt added with 2 lines", cmp.Files)
+	}
+
+	// An ancestor has nothing of its own
+	cmp, err = CompareCommits(r, base, a2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp.MergeBase != base.String() || len(cmp.OnlyA) != 0 || len(cmp.OnlyB) != 2 {
+		t.Errorf("base vs main = base %s, %d/%d commits", cmp.MergeBase, len(cmp.OnlyA), len(cmp.OnlyB))
+	}
+
+	cmp, err = CompareCommits(r, a2, a2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmp.OnlyA)+len(cmp.OnlyB)+len(cmp.Files) != 0 {
+		t.Errorf("self compare = %+v", cmp)
+	}
+
+	// Unrelated histories have no merge-base
+	orphan := commitFiles(t, r, map[string]string{"o.txt": "o\n"}, "orphan", 4*time.Minute)
+	cmp, err = CompareCommits(r, a2, orphan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmp.MergeBase != "" || len(cmp.OnlyA) != 3 || len(cmp.OnlyB) != 1 {
+		t.Errorf("unrelated = base %q, %d/%d commits", cmp.MergeBase, len(cmp.OnlyA), len(cmp.OnlyB))
+	}
+}
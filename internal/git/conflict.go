@@ -0,0 +1,170 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+)
+
+// Index stages of an unmerged path
+const (
+	stageBase   index.Stage = 1
+	stageOurs   index.Stage = 2
+	stageTheirs index.Stage = 3
+)
+
+// Conflict is an unmerged path: the versions recorded in index stages 1-3.
+// A side without the file has a zero hash.
+type Conflict struct {
+	Path   string
+	Base   plumbing.Hash
+	Ours   plumbing.Hash
+	Theirs plumbing.Hash
+}
+
+// Code is the two-letter `git status --short` code, e.g. UU or DU
+func (c Conflict) Code() string {
+	base, ours, theirs := !c.Base.IsZero(), !c.Ours.IsZero(), !c.Theirs.IsZero()
+	switch {
+	case ours && theirs && !base:
+		return "AA"
+	case ours && theirs:
+		return "UU"
+	case ours && !base:
+		return "AU"
+	case theirs && !base:
+		return "UA"
+	case ours:
+		return "UD"
+	case theirs:
+		return "DU"
+	}
+	return "DD"
+}
+
+// Type describes the conflict the way `git status` does
+func (c Conflict) Type() string {
+	return map[string]string{
+		"UU": "both modified",
+		"AA": "both added",
+		"UD": "deleted by them",
+		"DU": "deleted by us",
+		"AU": "added by us",
+		"UA": "added by them",
+		"DD": "both deleted",
+	}[c.Code()]
+}
+
+// indexConflicts collects the unmerged entries of the index by path
+func indexConflicts(idx *index.Index) []Conflict {
+	byPath := map[string]*Conflict{}
+	var conflicts []*Conflict
+	for _, e := range idx.Entries {
+		if e.Stage == stageMerged {
+			continue
+		}
+		c, ok := byPath[e.Name]
+		if !ok {
+			c = &Conflict{Path: e.Name}
+			byPath[e.Name] = c
+			conflicts = append(conflicts, c)
+		}
+		switch e.Stage {
+		case stageBase:
+			c.Base = e.Hash
+		case stageOurs:
+			c.Ours = e.Hash
+		case stageTheirs:
+			c.Theirs = e.Hash
+		}
+	}
+
+	out := make([]Conflict, len(conflicts))
+	for i, c := range conflicts {
+		out[i] = *c
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Path < out[j].Path })
+	return out
+}
+
+// GetConflicts lists the unmerged paths of the index, sorted by path
+func GetConflicts(r *git.Repository) ([]Conflict, error) {
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	return indexConflicts(idx), nil
+}
+
+// ConflictVersion is one version of a conflicted file
+type ConflictVersion struct {
+	Label   string // base, ours, theirs or worktree
+	Content string
+	Exists  bool
+	Binary  bool
+}
+
+// ConflictFiles is everything needed to look at a conflict: each version
+// of the file and how ours and theirs changed the base
+type ConflictFiles struct {
+	Conflict
+	Versions []ConflictVersion // Base, ours, theirs and the worktree file with its markers
+	Diffs    []FileDiff        // Base to ours, base to theirs
+}
+
+// GetConflictFiles loads the versions of a conflicted path
+func GetConflictFiles(r *git.Repository, path string) (*ConflictFiles, error) {
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	var files *ConflictFiles
+	for _, c := range indexConflicts(idx) {
+		if c.Path == path {
+			files = &ConflictFiles{Conflict: c}
+		}
+	}
+	if files == nil {
+		return nil, fmt.Errorf("%s has no conflict", path)
+	}
+
+	var stages [4]fileVersion
+	for _, e := range idx.Entries {
+		if e.Name != path || e.Stage == stageMerged {
+			continue
+		}
+		content, err := blobContent(r, e.Hash)
+		if err != nil {
+			return nil, err
+		}
+		stages[e.Stage] = fileVersion{content: content, mode: e.Mode, ok: true}
+	}
+	base, ours, theirs := stages[stageBase], stages[stageOurs], stages[stageTheirs]
+
+	w, err := r.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	var work fileVersion
+	if work.content, work.mode, work.ok, err = readWorktreeFile(w, path); err != nil {
+		return nil, err
+	}
+
+	labels := []string{"base", "ours", "theirs", "worktree"}
+	for i, v := range []fileVersion{base, ours, theirs, work} {
+		files.Versions = append(files.Versions, ConflictVersion{
+			Label:   labels[i],
+			Content: string(v.content),
+			Exists:  v.ok,
+			Binary:  isBinary(v.content),
+		})
+	}
+	files.Diffs = []FileDiff{
+		textDiff(path, base, ours, DefaultContext),
+		textDiff(path, base, theirs, DefaultContext),
+	}
+	return files, nil
+}
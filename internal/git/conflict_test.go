@@ -0,0 +1,107 @@
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+)
+
+func TestConflicts(t *testing.T) {
+	r, dir := newTestRepo(t)
+	commitFile(t, r, dir, "both.txt", "a\nb\nc\n", "base")
+	commitFile(t, r, dir, "clean.txt", "clean\n", "base")
+
+	blob := func(content string) index.Entry {
+		hash, err := writeBlob(r, []byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return index.Entry{Hash: hash, Mode: filemode.Regular}
+	}
+	idx, err := r.Storer.Index()
+	if err != nil {
+		t.Fatal(err)
+	}
+	removeIndexEntry(idx, "both.txt")
+	stages := map[string][]string{ // base, ours, theirs; empty when missing
+		"both.txt":    {"a\nb\nc\n", "a\nOURS\nc\n", "a\nTHEIRS\nc\n"},
+		"delus.txt":   {"keep\n", "", "changed\n"},
+		"delthem.txt": {"keep\n", "changed\n", ""},
+		"added.txt":   {"", "o\n", "t\n"},
+	}
+	for path, contents := range stages {
+		for i, content := range contents {
+			if content == "" {
+				continue
+			}
+			e := blob(content)
+			e.Name, e.Stage = path, index.Stage(i+1)
+			idx.Entries = append(idx.Entries, &e)
+		}
+	}
+	if err := r.Storer.SetIndex(idx); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "both.txt", "a\n<<<<<<< ours\nOURS\n=======\nTHEIRS\n>>>>>>> theirs\nc\n")
+	writeFile(t, dir, "delus.txt", "changed\n")
+	writeFile(t, dir, "delthem.txt", "changed\n")
+	writeFile(t, dir, "added.txt", "o\n")
+	writeFile(t, dir, "clean.txt", "edited\n")
+
+	ws, err := GetWorkingDirStatus(r, RenameOptions{Threshold: DefaultRenameThreshold})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []string
+	for _, f := range ws.Files {
+		rows = append(rows, f.Path+":"+f.Status+":"+f.Conflict)
+	}
+	want := "added.txt:U:both added both.txt:U:both modified clean.txt:M: delthem.txt:U:deleted by them delus.txt:U:deleted by us"
+	if got := strings.Join(rows, " "); got != want {
+		t.Errorf("status = %q; want %q", got, want)
+	}
+	if ws.Conflicted != 4 || ws.Modified != 1 || ws.Staged != 0 {
+		t.Errorf("counts = %d conflicted, %d modified, %d staged", ws.Conflicted, ws.Modified, ws.Staged)
+	}
+
+	files, err := GetConflictFiles(r, "both.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []string
+	for _, v := range files.Versions {
+		versions = append(versions, v.Label+"="+strings.Split(v.Content, "\n")[1])
+	}
+	if got, want := strings.Join(versions, " "), "base=b ours=OURS theirs=THEIRS worktree=<<<<<<< ours"; got != want {
+		t.Errorf("versions = %q; want %q", got, want)
+	}
+	if len(files.Diffs) != 2 || len(files.Diffs[0].Hunks) != 1 || files.Diffs[1].Hunks[0].Lines[2].Text != "THEIRS" {
+		t.Errorf("diffs = %+v", files.Diffs)
+	}
+
+	files, err = GetConflictFiles(r, "delus.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files.Code() != "DU" || files.Versions[1].Exists || !files.Versions[2].Exists || files.Diffs[0].Status() != "D" {
+		t.Errorf("delus.txt = %s, versions %+v", files.Code(), files.Versions)
+	}
+	if _, err := GetConflictFiles(r, "clean.txt"); err == nil {
+		t.Error("clean.txt loaded as a conflict")
+	}
+
+	// Staging the file with its resolution marks it resolved, even when the
+	// worktree matches one of the stages
+	if err := StagePaths(r, "added.txt"); err != nil {
+		t.Fatal(err)
+	}
+	conflicts, err := GetConflicts(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conflicts) != 3 || conflicts[0].Path != "both.txt" {
+		t.Errorf("conflicts after resolving added.txt = %+v", conflicts)
+	}
+}
@@ -0,0 +1,139 @@
+package git
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// FileChange is a single path changed between two trees
+type FileChange struct {
+	Path    string
+	OldPath string // Set when the path was renamed
+	Status  string // A, M, D or R
+}
+
+// commitTree returns the tree of the given commit, or an empty tree for the zero hash
+func commitTree(r *git.Repository, hash plumbing.Hash) (*object.Tree, error) {
+	if hash.IsZero() {
+		return &object.Tree{}, nil
+	}
+	c, err := r.CommitObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	return c.Tree()
+}
+
+// treeChanges lists the paths that differ between two trees, sorted by path
+func treeChanges(from, to *object.Tree) ([]FileChange, error) {
+	changes, err := object.DiffTree(from, to)
+	if err != nil {
+		return nil, err
+	}
+	return fileChanges(changes)
+}
+
+func fileChanges(changes object.Changes) ([]FileChange, error) {
+	files := []FileChange{}
+	for _, ch := range changes {
+		fc, err := fileChange(ch)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, fc)
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+	return files, nil
+}
+
+func fileChange(ch *object.Change) (FileChange, error) {
+	action, err := ch.Action()
+	if err != nil {
+		return FileChange{}, err
+	}
+
+	switch action {
+	case merkletrie.Insert:
+		return FileChange{Path: ch.To.Name, Status: "A"}, nil
+	case merkletrie.Delete:
+		return FileChange{Path: ch.From.Name, Status: "D"}, nil
+	}
+
+	fc := FileChange{Path: ch.To.Name, Status: "M"}
+	if ch.From.Name != ch.To.Name {
+		fc.OldPath = ch.From.Name
+		fc.Status = "R"
+	}
+	return fc, nil
+}
+
+// FileStat is a changed path with its line counts
+type FileStat struct {
+	FileChange
+	Additions int
+	Deletions int
+	Binary    bool
+}
+
+// diffStat diffs two trees with rename detection and counts the added and
+// removed lines of every file. The patch is returned for rendering.
+func diffStat(from, to *object.Tree) ([]FileStat, *object.Patch, error) {
+	changes, err := object.DiffTreeWithOptions(context.Background(), from, to, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	patch, err := changes.Patch()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Patch keeps one file patch per change, in order
+	filePatches := patch.FilePatches()
+	stats := make([]FileStat, 0, len(changes))
+	for i, ch := range changes {
+		fc, err := fileChange(ch)
+		if err != nil {
+			return nil, nil, err
+		}
+		st := FileStat{FileChange: fc}
+		if i < len(filePatches) {
+			st.Binary = filePatches[i].IsBinary()
+			st.Additions, st.Deletions = countLines(filePatches[i])
+		}
+		stats = append(stats, st)
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Path < stats[j].Path
+	})
+	return stats, patch, nil
+}
+
+// countLines returns the number of added and deleted lines in a file patch
+func countLines(fp diff.FilePatch) (added, deleted int) {
+	for _, chunk := range fp.Chunks() {
+		content := chunk.Content()
+		n := strings.Count(content, "\n")
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			n++
+		}
+		switch chunk.Type() {
+		case diff.Add:
+			added += n
+		case diff.Delete:
+			deleted += n
+		}
+	}
+	return added, deleted
+}
@@ -0,0 +1,200 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ForecastFile is a file the merge would bring in
+type ForecastFile struct {
+	Path        string
+	Status      string // A, M or D on the merged branch
+	BothChanged bool   // Also changed on the current branch, needs a content merge
+	Conflict    bool
+	Reason      string // Why it conflicts, e.g. "both modified"
+}
+
+// MergeForecast predicts merging theirs into ours
+type MergeForecast struct {
+	Ours        string // Hashes the forecast was made for
+	Theirs      string
+	MergeBase   string // Empty when the histories are unrelated
+	UpToDate    bool   // Theirs is already merged
+	FastForward bool   // Ours has nothing of its own, the merge just moves the branch
+	Files       []ForecastFile
+}
+
+// Conflicts counts the files that would conflict
+func (f *MergeForecast) Conflicts() int {
+	n := 0
+	for _, file := range f.Files {
+		if file.Conflict {
+			n++
+		}
+	}
+	return n
+}
+
+// ForecastMerge runs a three-way merge of theirs into ours in memory, like
+// `git merge` with the default strategy, and reports per file whether it
+// merges cleanly. Nothing is written. Renames are not detected: a file
+// renamed on one side and modified on the other is forecast as a
+// delete/modify conflict, which git's rename detection may resolve.
+func ForecastMerge(r *git.Repository, ours, theirs plumbing.Hash) (*MergeForecast, error) {
+	f := &MergeForecast{Ours: ours.String(), Theirs: theirs.String()}
+	if ours == theirs {
+		f.UpToDate = true
+		return f, nil
+	}
+
+	co, err := r.CommitObject(ours)
+	if err != nil {
+		return nil, err
+	}
+	ct, err := r.CommitObject(theirs)
+	if err != nil {
+		return nil, err
+	}
+
+	bases, err := co.MergeBase(ct)
+	if err != nil {
+		return nil, err
+	}
+	base := plumbing.ZeroHash
+	if len(bases) > 0 {
+		base = bases[0].Hash
+		f.MergeBase = base.String()
+	}
+	f.UpToDate = base == theirs
+	f.FastForward = base == ours
+	if f.UpToDate {
+		return f, nil
+	}
+
+	baseTree, err := commitTree(r, base)
+	if err != nil {
+		return nil, err
+	}
+	oursTree, err := co.Tree()
+	if err != nil {
+		return nil, err
+	}
+	theirsTree, err := ct.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	theirChanges, err := treeChanges(baseTree, theirsTree)
+	if err != nil {
+		return nil, err
+	}
+	ourChanges, err := treeChanges(baseTree, oursTree)
+	if err != nil {
+		return nil, err
+	}
+	changedByUs := make(map[string]bool, len(ourChanges))
+	var addedByUs []string // Changed paths still there on our side
+	for _, ch := range ourChanges {
+		changedByUs[ch.Path] = true
+		if ch.Status != "D" {
+			addedByUs = append(addedByUs, ch.Path)
+		}
+	}
+
+	f.Files = make([]ForecastFile, 0, len(theirChanges))
+	for _, ch := range theirChanges {
+		file := ForecastFile{Path: ch.Path, Status: ch.Status}
+		if changedByUs[ch.Path] {
+			file.BothChanged = true
+			if file.Reason, err = mergeConflict(r, ch.Path, baseTree, oursTree, theirsTree); err != nil {
+				return nil, err
+			}
+			file.Conflict = file.Reason != ""
+		}
+		if !file.Conflict && ch.Status != "D" {
+			if clash := pathClash(ch.Path, addedByUs); clash != "" {
+				file.Conflict = true
+				file.Reason = "file/directory conflict with " + clash
+			}
+		}
+		f.Files = append(f.Files, file)
+	}
+	return f, nil
+}
+
+// pathClash finds a path among ours that is a parent directory or a child
+// of path, so one side has a file where the other has a directory
+func pathClash(path string, ours []string) string {
+	for _, o := range ours {
+		if strings.HasPrefix(path, o+"/") || strings.HasPrefix(o, path+"/") {
+			return o
+		}
+	}
+	return ""
+}
+
+// mergeConflict merges a file changed on both sides and describes the
+// conflict, or returns "" when it merges cleanly
+func mergeConflict(r *git.Repository, path string, base, ours, theirs *object.Tree) (string, error) {
+	b, bOK := treeEntry(base, path)
+	o, oOK := treeEntry(ours, path)
+	t, tOK := treeEntry(theirs, path)
+
+	switch {
+	case oOK == tOK && o == t:
+		return "", nil // Same change on both sides
+	case !oOK && !tOK:
+		return "", nil // Deleted on both sides
+	case !oOK:
+		return "deleted by us", nil
+	case !tOK:
+		return "deleted by them", nil
+	case o.Mode == filemode.Submodule || t.Mode == filemode.Submodule:
+		return "submodule changed on both sides", nil
+	}
+
+	if bOK && o.Mode != t.Mode && o.Mode != b.Mode && t.Mode != b.Mode {
+		return "file mode changed on both sides", nil
+	}
+
+	var baseContent []byte
+	if bOK {
+		content, err := blobContent(r, b.Hash)
+		if err != nil {
+			return "", err
+		}
+		baseContent = content
+	}
+	oursContent, err := blobContent(r, o.Hash)
+	if err != nil {
+		return "", err
+	}
+	theirsContent, err := blobContent(r, t.Hash)
+	if err != nil {
+		return "", err
+	}
+
+	if isBinary(baseContent) || isBinary(oursContent) || isBinary(theirsContent) {
+		return "binary file changed on both sides", nil
+	}
+	if _, ok := mergeText(string(baseContent), string(oursContent), string(theirsContent)); !ok {
+		if !bOK {
+			return "added by both", nil
+		}
+		return "both modified", nil
+	}
+	return "", nil
+}
+
+// treeEntry looks up a file in a tree
+func treeEntry(t *object.Tree, path string) (treeFile, bool) {
+	entry, err := t.FindEntry(path)
+	if err != nil || entry.Mode == filemode.Dir {
+		return treeFile{}, false
+	}
+	return treeFile{Hash: entry.Hash, Mode: entry.Mode}, true
+}
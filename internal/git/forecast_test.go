@@ -0,0 +1,124 @@
+package git
+
+import (
+	"testing"
+	"time"
+)
+
+func TestForecastMerge(t *testing.T) {
+	r, _ := newTestRepo(t)
+	base := commitFiles(t, r, map[string]string{
+		"clean.txt":  "a\n",
+		"merge.txt":  "1\n2\n3\n4\n5\n",
+		"clash.txt":  "x\n",
+		"delete.txt": "d\n",
+		"same.txt":   "s\n",
+	}, "base", 0)
+	ours := commitFiles(t, r, map[string]string{
+		"clean.txt":  "a\n",
+		"merge.txt":  "one\n2\n3\n4\n5\n",
+		"clash.txt":  "ours\n",
+		"delete.txt": "changed by us\n",
+		"same.txt":   "S\n",
+		"both.txt":   "ours\n",
+	}, "ours", time.Minute, base)
+	theirs := commitFiles(t, r, map[string]string{
+		"clean.txt": "a\nb\n",
+		"merge.txt": "1\n2\n3\n4\nfive\n",
+		"clash.txt": "theirs\n",
+		"same.txt":  "S\n",
+		"both.txt":  "theirs\n",
+		"new.txt":   "n\n",
+	}, "theirs", 2*time.Minute, base)
+
+	f, err := ForecastMerge(r, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.MergeBase != base.String() || f.UpToDate || f.FastForward {
+		t.Errorf("forecast = %+v", f)
+	}
+
+	want := map[string]struct {
+		both   bool
+		reason string
+	}{
+		"clean.txt":  {false, ""},
+		"new.txt":    {false, ""},
+		"merge.txt":  {true, ""},
+		"same.txt":   {true, ""},
+		"clash.txt":  {true, "both modified"},
+		"delete.txt": {true, "deleted by them"},
+		"both.txt":   {true, "added by both"},
+	}
+	if len(f.Files) != len(want) {
+		t.Errorf("got %d files; want %d: %+v", len(f.Files), len(want), f.Files)
+	}
+	for _, file := range f.Files {
+		w, ok := want[file.Path]
+		if !ok {
+			t.Errorf("unexpected file %s", file.Path)
+			continue
+		}
+		if file.BothChanged != w.both || file.Reason != w.reason || file.Conflict != (w.reason != "") {
+			t.Errorf("%s = %+v; want both changed %v, reason %q", file.Path, file, w.both, w.reason)
+		}
+	}
+	if f.Conflicts() != 3 {
+		t.Errorf("Conflicts() = %d; want 3", f.Conflicts())
+	}
+
+	// Merging an ancestor does nothing, merging into one is a fast-forward
+	f, err = ForecastMerge(r, ours, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.UpToDate || len(f.Files) != 0 {
+		t.Errorf("ancestor forecast = %+v", f)
+	}
+	f, err = ForecastMerge(r, base, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.FastForward || f.Conflicts() != 0 || len(f.Files) != 7 {
+		t.Errorf("fast-forward forecast = %+v", f)
+	}
+}
+
+func TestForecastFileDirectoryClash(t *testing.T) {
+	r, _ := newTestRepo(t)
+	base := commitFiles(t, r, map[string]string{"keep.txt": "k\n", "old": "o\n"}, "base", 0)
+	// Ours adds a file where theirs adds a directory and the reverse; theirs
+	// also replaces the file "old" with a directory we did not touch
+	ours := commitFiles(t, r, map[string]string{
+		"keep.txt":   "k\n",
+		"old":        "o\n",
+		"a":          "file\n",
+		"dir/b/c.go": "c\n",
+	}, "ours", time.Minute, base)
+	theirs := commitFiles(t, r, map[string]string{
+		"keep.txt":   "k\n",
+		"a/inner.go": "inner\n",
+		"dir/b":      "file\n",
+		"old/new.go": "n\n",
+	}, "theirs", 2*time.Minute, base)
+
+	f, err := ForecastMerge(r, ours, theirs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"a/inner.go": "file/directory conflict with a",
+		"dir/b":      "file/directory conflict with dir/b/c.go",
+		"old":        "",
+		"old/new.go": "",
+	}
+	if len(f.Files) != len(want) {
+		t.Errorf("got %d files; want %d: %+v", len(f.Files), len(want), f.Files)
+	}
+	for _, file := range f.Files {
+		if reason, ok := want[file.Path]; !ok || file.Reason != reason || file.Conflict != (reason != "") {
+			t.Errorf("%s = %+v; want reason %q", file.Path, file, reason)
+		}
+	}
+}
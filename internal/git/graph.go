@@ -0,0 +1,118 @@
+package git
+
+import (
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// GetCommitGraph returns up to n commits reachable from the given commit for
+// drawing as a graph: in topological order, so a commit always comes after
+// all of its children even when commit dates are skewed, newest first
+// otherwise, and decorated with the refs pointing at it.
+// Parents outside the window are still listed in Commit.Parents.
+func GetCommitGraph(r *git.Repository, from plumbing.Hash, n int) ([]Commit, error) {
+	if from.IsZero() {
+		return nil, nil
+	}
+
+	refs, err := refDecorations(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var commits []Commit
+	err = walkTopo(r, from, func(c *object.Commit) (bool, error) {
+		commit := newCommit(c)
+		commit.Refs = refs[c.Hash]
+		commits = append(commits, commit)
+		return len(commits) < n, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return commits, nil
+}
+
+type DecorationKind int
+
+const (
+	DecorationHead DecorationKind = iota // HEAD, or "HEAD -> branch"
+	DecorationBranch
+	DecorationRemote
+	DecorationTag
+)
+
+// Decoration is a ref name shown next to a commit
+type Decoration struct {
+	Name string // As git prints it, e.g. "HEAD -> main" or "tag: v1.0"
+	Kind DecorationKind
+}
+
+// refDecorations maps commits to the names pointing at them, ordered like
+// `git log --decorate`: HEAD first, then local branches, remotes and tags
+func refDecorations(r *git.Repository) (map[plumbing.Hash][]Decoration, error) {
+	found := map[plumbing.Hash][]Decoration{}
+
+	head, err := r.Head()
+	headBranch := plumbing.ReferenceName("")
+	if err == nil {
+		if head.Name().IsBranch() {
+			headBranch = head.Name()
+		} else {
+			found[head.Hash()] = append(found[head.Hash()], Decoration{"HEAD", DecorationHead})
+		}
+	}
+
+	iter, err := r.References()
+	if err != nil {
+		return nil, err
+	}
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference {
+			return nil
+		}
+
+		name := ref.Name()
+		var d Decoration
+		switch {
+		case name == headBranch:
+			d = Decoration{"HEAD -> " + name.Short(), DecorationHead}
+		case name.IsBranch():
+			d = Decoration{name.Short(), DecorationBranch}
+		case name.IsRemote():
+			d = Decoration{name.Short(), DecorationRemote}
+		case name.IsTag():
+			d = Decoration{"tag: " + name.Short(), DecorationTag}
+		default:
+			return nil // refs/stash, notes and the like
+		}
+
+		target := ref.Hash()
+		if name.IsTag() {
+			c, err := peelCommit(r, target)
+			if err != nil {
+				return nil
+			}
+			target = c.Hash
+		}
+		found[target] = append(found[target], d)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ds := range found {
+		sort.Slice(ds, func(i, j int) bool {
+			if ds[i].Kind != ds[j].Kind {
+				return ds[i].Kind < ds[j].Kind
+			}
+			return ds[i].Name < ds[j].Name
+		})
+	}
+	return found, nil
+}
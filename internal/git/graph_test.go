@@ -0,0 +1,102 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// commitAt commits a file with both signatures at the given offset from testSignature
+func commitAt(t *testing.T, r *git.Repository, dir, name, content, msg string, offset time.Duration, parents ...plumbing.Hash) plumbing.Hash {
+	t.Helper()
+	writeFile(t, dir, name, content)
+	w, _ := r.Worktree()
+	if _, err := w.Add(name); err != nil {
+		t.Fatal(err)
+	}
+	sig := testSignature
+	sig.When = sig.When.Add(offset)
+	h, err := w.Commit(msg, &git.CommitOptions{Author: &sig, Committer: &sig, Parents: parents})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func TestGetCommitGraph(t *testing.T) {
+	r, dir := newTestRepo(t)
+	base := commitAt(t, r, dir, "a.txt", "base\n", "base", 0)
+	side := commitAt(t, r, dir, "b.txt", "side\n", "side", time.Minute)
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/heads/side", side)); err != nil {
+		t.Fatal(err)
+	}
+	main := commitAt(t, r, dir, "a.txt", "main\n", "main", 2*time.Minute, base)
+	merge := commitAt(t, r, dir, "a.txt", "merged\n", "merge", 3*time.Minute, main, side)
+	if _, err := r.CreateTag("v1", merge, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/remotes/origin/master", main)); err != nil {
+		t.Fatal(err)
+	}
+
+	commits, err := GetCommitGraph(r, merge, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []plumbing.Hash{merge, main, side, base}
+	if len(commits) != len(want) {
+		t.Fatalf("got %d commits; want %d", len(commits), len(want))
+	}
+	for i, h := range want {
+		if commits[i].Hash != h.String() {
+			t.Errorf("commit %d = %s; want %s", i, commits[i].Hash[:7], h.String()[:7])
+		}
+	}
+
+	if len(commits[0].Parents) != 2 {
+		t.Errorf("merge parents = %v", commits[0].Parents)
+	}
+	refs := commits[0].Refs
+	if len(refs) != 2 || refs[0] != (Decoration{"HEAD -> master", DecorationHead}) || refs[1] != (Decoration{"tag: v1", DecorationTag}) {
+		t.Errorf("merge decorations = %v", refs)
+	}
+	if refs := commits[1].Refs; len(refs) != 1 || refs[0].Kind != DecorationRemote {
+		t.Errorf("main decorations = %v", refs)
+	}
+
+	// A truncated window still reports parents beyond it
+	commits, err = GetCommitGraph(r, merge, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(commits) != 2 || commits[1].Parents[0] != base.String() {
+		t.Errorf("truncated graph = %+v", commits)
+	}
+}
+
+func TestGetCommitGraphSkewedDates(t *testing.T) {
+	// merge has parents b and c, c's parent is b, yet b is dated after c.
+	// Newest first would list b before its child c.
+	r, dir := newTestRepo(t)
+	root := commitAt(t, r, dir, "a.txt", "root\n", "root", 0)
+	b := commitAt(t, r, dir, "a.txt", "b\n", "b", 3*time.Minute, root)
+	c := commitAt(t, r, dir, "a.txt", "c\n", "c", time.Minute, b)
+	merge := commitAt(t, r, dir, "a.txt", "merged\n", "merge", 4*time.Minute, b, c)
+
+	commits, err := GetCommitGraph(r, merge, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []plumbing.Hash{merge, c, b, root}
+	if len(commits) != len(want) {
+		t.Fatalf("got %d commits; want %d", len(commits), len(want))
+	}
+	for i, h := range want {
+		if commits[i].Hash != h.String() {
+			t.Errorf("commit %d = %s; want %s", i, commits[i].Hash[:7], h.String()[:7])
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package git
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// FileRevision is a commit that changed a file, with the file's change in it
+type FileRevision struct {
+	Commit
+	Path      string // Name of the file in this commit
+	OldPath   string // Set when this commit renamed the file
+	Status    string // A, M or R
+	Additions int
+	Deletions int
+	Binary    bool
+}
+
+// FileHistory lists the commits that changed the file at path, newest first,
+// following it across renames like `git log --follow`. path names the file
+// as it is in the from commit. As in `git log`, a merge that took the file
+// unchanged from one of its parents is skipped and only that parent is
+// followed.
+func FileHistory(r *git.Repository, path string, from plumbing.Hash) ([]FileRevision, error) {
+	start, err := r.CommitObject(from)
+	if err != nil {
+		return nil, err
+	}
+	if blob, _ := fileBlob(start, path); blob.IsZero() {
+		return nil, fmt.Errorf("%s: no such file in %s", path, from.String()[:7])
+	}
+
+	// The name of the file differs per commit once a rename is crossed
+	pathAt := map[plumbing.Hash]string{from: path}
+	queue := &commitHeap{start}
+
+	follow := func(hash plumbing.Hash, name string) error {
+		if _, ok := pathAt[hash]; ok {
+			return nil
+		}
+		pathAt[hash] = name
+		c, err := r.CommitObject(hash)
+		if err == plumbing.ErrObjectNotFound {
+			return nil // Shallow boundary
+		}
+		if err != nil {
+			return err
+		}
+		heap.Push(queue, c)
+		return nil
+	}
+
+	var history []FileRevision
+	for queue.Len() > 0 {
+		c := heap.Pop(queue).(*object.Commit)
+		name := pathAt[c.Hash]
+		own, err := fileBlob(c, name)
+		if err != nil {
+			return nil, err
+		}
+
+		parents := make([]*object.Commit, 0, c.NumParents())
+		for _, p := range c.ParentHashes {
+			pc, err := r.CommitObject(p)
+			if err == plumbing.ErrObjectNotFound {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			parents = append(parents, pc)
+		}
+
+		// Unchanged from a parent: the file's history continues there alone
+		same := false
+		for _, p := range parents {
+			if blob, _ := fileBlob(p, name); blob == own {
+				same = true
+				if err := follow(p.Hash, name); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+		if same {
+			continue
+		}
+
+		rev := FileRevision{Commit: newCommit(c), Path: name, Status: "A"}
+		var before plumbing.Hash
+		for i, p := range parents {
+			blob, _ := fileBlob(p, name)
+			if !blob.IsZero() {
+				if rev.Status == "A" {
+					rev.Status, before = "M", blob
+				}
+				if err := follow(p.Hash, name); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			if i > 0 {
+				continue
+			}
+
+			// New under this name in the first parent, it may have been moved
+			oldPath, err := renamedFrom(p, c, name)
+			if err != nil {
+				return nil, err
+			}
+			if oldPath != "" {
+				rev.Status, rev.OldPath = "R", oldPath
+				before, _ = fileBlob(p, oldPath)
+				if err := follow(p.Hash, oldPath); err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		if rev.Additions, rev.Deletions, rev.Binary, err = blobDelta(r, before, own); err != nil {
+			return nil, err
+		}
+		history = append(history, rev)
+	}
+
+	return history, nil
+}
+
+// fileBlob returns the blob of the file at path in c, zero when there is no such file
+func fileBlob(c *object.Commit, path string) (plumbing.Hash, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	entry, err := tree.FindEntry(path)
+	if err != nil || !entry.Mode.IsFile() {
+		return plumbing.ZeroHash, nil
+	}
+	return entry.Hash, nil
+}
+
+// renamedFrom returns the path in parent that c renamed to path, if any,
+// using the same similarity detection as the commit view
+func renamedFrom(parent, c *object.Commit, path string) (string, error) {
+	from, err := parent.Tree()
+	if err != nil {
+		return "", err
+	}
+	to, err := c.Tree()
+	if err != nil {
+		return "", err
+	}
+
+	changes, err := object.DiffTreeWithOptions(context.Background(), from, to, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return "", err
+	}
+	for _, ch := range changes {
+		if ch.To.Name == path && ch.From.Name != "" && ch.From.Name != path {
+			return ch.From.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// blobDelta counts the lines added and removed going from one blob to
+// another. A zero hash stands for a missing file.
+func blobDelta(r *git.Repository, from, to plumbing.Hash) (added, deleted int, binary bool, err error) {
+	var before, after []byte
+	if !from.IsZero() {
+		if before, err = blobContent(r, from); err != nil {
+			return 0, 0, false, err
+		}
+	}
+	if after, err = blobContent(r, to); err != nil {
+		return 0, 0, false, err
+	}
+	if isBinary(before) || isBinary(after) {
+		return 0, 0, true, nil
+	}
+
+	for _, d := range diff.Do(string(before), string(after)) {
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			added += len(splitLines(d.Text))
+		case diffmatchpatch.DiffDelete:
+			deleted += len(splitLines(d.Text))
+		}
+	}
+	return added, deleted, false, nil
+}
+
+// ListFiles returns the path of every file in the tree of a commit, sorted
+func ListFiles(r *git.Repository, from plumbing.Hash) ([]string, error) {
+	tree, err := commitTree(r, from)
+	if err != nil {
+		return nil, err
+	}
+	files, err := treeFiles(tree)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(files))
+	for path, f := range files {
+		if f.Mode.IsFile() { // Not submodules
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
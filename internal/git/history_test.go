@@ -0,0 +1,72 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestFileHistory(t *testing.T) {
+	r, dir := newTestRepo(t)
+	add := commitAt(t, r, dir, "a.txt", "1\n2\n3\n4\n5\n", "add a", 0)
+	edit := commitAt(t, r, dir, "a.txt", "1\ntwo\n3\n4\n5\n", "edit a", time.Minute)
+	commitAt(t, r, dir, "other.txt", "x\n", "unrelated", 2*time.Minute)
+
+	// Move a.txt to b.txt and touch one line on the way
+	w, _ := r.Worktree()
+	if _, err := w.Move("a.txt", "b.txt"); err != nil {
+		t.Fatal(err)
+	}
+	rename := commitAt(t, r, dir, "b.txt", "1\ntwo\n3\n4\nfive\n", "move a to b", 3*time.Minute)
+
+	// A merge taking b.txt unchanged from the side branch is not listed
+	main := commitAt(t, r, dir, "x.txt", "x\n", "main work", 4*time.Minute, rename)
+	side := commitAt(t, r, dir, "b.txt", "1\ntwo\n3\n4\nfive\nsix\n", "side edit", 5*time.Minute, rename)
+	merge := commitAt(t, r, dir, "b.txt", "1\ntwo\n3\n4\nfive\nsix\n", "merge", 6*time.Minute, main, side)
+
+	history, err := FileHistory(r, "b.txt", merge)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		hash         plumbing.Hash
+		status, path string
+		oldPath      string
+		add, del     int
+	}{
+		{side, "M", "b.txt", "", 1, 0},
+		{rename, "R", "b.txt", "a.txt", 1, 1},
+		{edit, "M", "a.txt", "", 1, 1},
+		{add, "A", "a.txt", "", 5, 0},
+	}
+	if len(history) != len(want) {
+		t.Fatalf("got %d revisions; want %d: %+v", len(history), len(want), history)
+	}
+	for i, w := range want {
+		got := history[i]
+		if got.Hash != w.hash.String() || got.Status != w.status || got.Path != w.path || got.OldPath != w.oldPath ||
+			got.Additions != w.add || got.Deletions != w.del {
+			t.Errorf("revision %d = %s %s %s<-%q +%d -%d; want %s %s %s<-%q +%d -%d", i,
+				got.Hash[:7], got.Status, got.Path, got.OldPath, got.Additions, got.Deletions,
+				w.hash.String()[:7], w.status, w.path, w.oldPath, w.add, w.del)
+		}
+	}
+
+	if _, err := FileHistory(r, "a.txt", merge); err == nil {
+		t.Error("history of a file missing at the revision should fail")
+	}
+	if _, err := FileHistory(r, "missing.txt", merge); err == nil {
+		t.Error("history of an unknown file should fail")
+	}
+
+	// Starting before the rename lists the old name only
+	history, err = FileHistory(r, "a.txt", edit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 2 || history[0].Hash != edit.String() {
+		t.Errorf("history at edit = %+v", history)
+	}
+}
@@ -0,0 +1,196 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/diff"
+)
+
+// DefaultContext is the number of unchanged lines kept around each change,
+// as in `git diff`
+const DefaultContext = 3
+
+type DiffLineKind byte
+
+const (
+	DiffContext DiffLineKind = ' '
+	DiffAdd     DiffLineKind = '+'
+	DiffDel     DiffLineKind = '-'
+)
+
+// DiffLine is one line of a hunk. OldLine and NewLine are 1-based line
+// numbers on each side, 0 on the side the line does not exist.
+type DiffLine struct {
+	Kind      DiffLineKind
+	OldLine   int
+	NewLine   int
+	Text      string // Without the line terminator
+	NoNewline bool   // Last line of the file without a trailing newline
+}
+
+// Hunk is a run of changes with its surrounding context
+type Hunk struct {
+	OldStart, OldLines int
+	NewStart, NewLines int
+	Lines              []DiffLine
+}
+
+// Header renders the hunk range line, e.g. "@@ -1,3 +1,4 @@"
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%s +%s @@", hunkRange(h.OldStart, h.OldLines), hunkRange(h.NewStart, h.NewLines))
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
+
+// FileDiff is the diff of one file split into hunks
+type FileDiff struct {
+	OldPath string // Empty for an added file
+	NewPath string // Empty for a deleted file
+	OldMode filemode.FileMode
+	NewMode filemode.FileMode
+	Binary  bool
+	Hunks   []Hunk
+}
+
+// Path is the path the file has after the change
+func (f FileDiff) Path() string {
+	if f.NewPath != "" {
+		return f.NewPath
+	}
+	return f.OldPath
+}
+
+// Status is A, D, R or M like FileChange.Status
+func (f FileDiff) Status() string {
+	switch {
+	case f.OldPath == "":
+		return "A"
+	case f.NewPath == "":
+		return "D"
+	case f.OldPath != f.NewPath:
+		return "R"
+	}
+	return "M"
+}
+
+// ModeChanged reports a mode change (e.g. made executable) on a file that exists on both sides
+func (f FileDiff) ModeChanged() bool {
+	return f.OldPath != "" && f.NewPath != "" && f.OldMode != f.NewMode
+}
+
+// FileDiffs splits a patch into per-file hunks with the given amount of context
+func FileDiffs(p diff.Patch, context int) []FileDiff {
+	var files []FileDiff
+	for _, fp := range p.FilePatches() {
+		from, to := fp.Files()
+
+		var f FileDiff
+		if from != nil {
+			f.OldPath, f.OldMode = from.Path(), from.Mode()
+		}
+		if to != nil {
+			f.NewPath, f.NewMode = to.Path(), to.Mode()
+		}
+		f.Binary = fp.IsBinary()
+		if !f.Binary {
+			f.Hunks = buildHunks(diffLines(fp.Chunks()), context)
+		}
+
+		files = append(files, f)
+	}
+	return files
+}
+
+// diffLines numbers every line of the chunks
+func diffLines(chunks []diff.Chunk) []DiffLine {
+	var lines []DiffLine
+	oldLine, newLine := 0, 0
+
+	for _, chunk := range chunks {
+		for _, text := range splitLines(chunk.Content()) {
+			l := DiffLine{
+				Text:      strings.TrimSuffix(text, "\n"),
+				NoNewline: !strings.HasSuffix(text, "\n"),
+			}
+			switch chunk.Type() {
+			case diff.Add:
+				newLine++
+				l.Kind, l.NewLine = DiffAdd, newLine
+			case diff.Delete:
+				oldLine++
+				l.Kind, l.OldLine = DiffDel, oldLine
+			default:
+				oldLine++
+				newLine++
+				l.Kind, l.OldLine, l.NewLine = DiffContext, oldLine, newLine
+			}
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+// buildHunks groups changed lines into hunks, merging changes whose context would overlap
+func buildHunks(lines []DiffLine, context int) []Hunk {
+	var changes []int
+	for i, l := range lines {
+		if l.Kind != DiffContext {
+			changes = append(changes, i)
+		}
+	}
+
+	var hunks []Hunk
+	for i := 0; i < len(changes); {
+		first := changes[i]
+		last := first
+		for i++; i < len(changes) && changes[i]-last-1 <= 2*context; i++ {
+			last = changes[i]
+		}
+
+		start := max(first-context, 0)
+		end := min(last+context+1, len(lines))
+		hunks = append(hunks, newHunk(lines, start, end))
+	}
+	return hunks
+}
+
+func newHunk(lines []DiffLine, start, end int) Hunk {
+	h := Hunk{Lines: lines[start:end]}
+
+	// Line numbers just before the hunk on each side
+	oldBefore, newBefore := 0, 0
+	for _, l := range lines[:start] {
+		if l.Kind != DiffAdd {
+			oldBefore++
+		}
+		if l.Kind != DiffDel {
+			newBefore++
+		}
+	}
+
+	for _, l := range h.Lines {
+		if l.Kind != DiffAdd {
+			h.OldLines++
+		}
+		if l.Kind != DiffDel {
+			h.NewLines++
+		}
+	}
+
+	// An empty side points at the line before the hunk, like git
+	h.OldStart, h.NewStart = oldBefore, newBefore
+	if h.OldLines > 0 {
+		h.OldStart++
+	}
+	if h.NewLines > 0 {
+		h.NewStart++
+	}
+	return h
+}
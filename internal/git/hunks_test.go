@@ -0,0 +1,84 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// numbered returns "line 1\n" ... "line n\n"
+func numbered(n int) []string {
+	lines := make([]string, n)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("line %d\n", i+1)
+	}
+	return lines
+}
+
+func TestFileDiffs(t *testing.T) {
+	r, dir := newTestRepo(t)
+
+	before := numbered(20)
+	commitFile(t, r, dir, "gone.txt", "a\nb\n", "base")
+	from := commitFile(t, r, dir, "file.txt", strings.Join(before, ""), "base")
+
+	after := append([]string{}, before...)
+	after[1] = "changed 2\n"
+	after[17] = "changed 18\n"
+	after[19] = "line 20" // Drop the trailing newline
+	commitFile(t, r, dir, "new.txt", "x\ny\n", "add")
+	w, _ := r.Worktree()
+	if _, err := w.Remove("gone.txt"); err != nil {
+		t.Fatal(err)
+	}
+	to := commitFile(t, r, dir, "file.txt", strings.Join(after, ""), "change")
+
+	fromTree, err := commitTree(r, from)
+	if err != nil {
+		t.Fatal(err)
+	}
+	toTree, err := commitTree(r, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	patch, err := fromTree.Patch(toTree)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]FileDiff{}
+	for _, f := range FileDiffs(patch, DefaultContext) {
+		files[f.Path()] = f
+	}
+
+	headers := func(f FileDiff) string {
+		var hs []string
+		for _, h := range f.Hunks {
+			hs = append(hs, h.Header())
+		}
+		return strings.Join(hs, " ")
+	}
+
+	f := files["file.txt"]
+	if f.Status() != "M" {
+		t.Errorf("file.txt status = %s", f.Status())
+	}
+	if got, want := headers(f), "@@ -1,5 +1,5 @@ @@ -15,6 +15,6 @@"; got != want {
+		t.Errorf("file.txt hunks = %s; want %s", got, want)
+	}
+	second := f.Hunks[1]
+	last := second.Lines[len(second.Lines)-1]
+	if last.Kind != DiffAdd || last.Text != "line 20" || !last.NoNewline || last.NewLine != 20 {
+		t.Errorf("last line = %+v; want the added line 20 without newline", last)
+	}
+	if first := second.Lines[0]; first.Kind != DiffContext || first.OldLine != 15 || first.NewLine != 15 {
+		t.Errorf("first context line = %+v", first)
+	}
+
+	if got, want := headers(files["new.txt"]), "@@ -0,0 +1,2 @@"; got != want || files["new.txt"].Status() != "A" {
+		t.Errorf("new.txt hunks = %s; want %s", got, want)
+	}
+	if got, want := headers(files["gone.txt"]), "@@ -1,2 +0,0 @@"; got != want || files["gone.txt"].Status() != "D" {
+		t.Errorf("gone.txt hunks = %s; want %s", got, want)
+	}
+}
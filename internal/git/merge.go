@@ -0,0 +1,150 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// lineEdit replaces base lines [start, end) with lines
+type lineEdit struct {
+	start, end int
+	lines      []string
+	side       int
+}
+
+// splitLines splits text into lines, keeping the line terminators so a
+// missing newline at end of file survives a round trip
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// lineEdits describes how to turn base into other as a list of edits on base
+func lineEdits(base, other string, side int) []lineEdit {
+	var edits []lineEdit
+	var cur *lineEdit
+	pos := 0
+
+	for _, d := range diff.Do(base, other) {
+		lines := splitLines(d.Text)
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			if cur != nil {
+				edits = append(edits, *cur)
+				cur = nil
+			}
+			pos += len(lines)
+		case diffmatchpatch.DiffDelete:
+			if cur == nil {
+				cur = &lineEdit{start: pos, end: pos, side: side}
+			}
+			pos += len(lines)
+			cur.end = pos
+		case diffmatchpatch.DiffInsert:
+			if cur == nil {
+				cur = &lineEdit{start: pos, end: pos, side: side}
+			}
+			cur.lines = append(cur.lines, lines...)
+		}
+	}
+	if cur != nil {
+		edits = append(edits, *cur)
+	}
+	return edits
+}
+
+// applyEdits rewrites base[start:end) with the given (ordered) edits of one side
+func applyEdits(base []string, start, end int, edits []lineEdit) []string {
+	var out []string
+	pos := start
+	for _, e := range edits {
+		out = append(out, base[pos:e.start]...)
+		out = append(out, e.lines...)
+		pos = e.end
+	}
+	return append(out, base[pos:end]...)
+}
+
+// mergeText performs a line based three-way merge of ours and theirs against
+// base. ok is false when both sides changed the same (or adjacent) lines in
+// different ways, which is where git would leave conflict markers.
+func mergeText(base, ours, theirs string) (merged string, ok bool) {
+	if ours == theirs {
+		return ours, true
+	}
+	if base == ours {
+		return theirs, true
+	}
+	if base == theirs {
+		return ours, true
+	}
+
+	baseLines := splitLines(base)
+	a := lineEdits(base, ours, 0)
+	b := lineEdits(base, theirs, 1)
+
+	var out []string
+	pos := 0
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		// Start a group with whichever edit comes first in base
+		var group []lineEdit
+		if j >= len(b) || (i < len(a) && a[i].start <= b[j].start) {
+			group = append(group, a[i])
+			i++
+		} else {
+			group = append(group, b[j])
+			j++
+		}
+		start, end := group[0].start, group[0].end
+
+		// Pull in every edit from either side that overlaps or touches the group
+		for {
+			if i < len(a) && a[i].start <= end {
+				group = append(group, a[i])
+				end = max(end, a[i].end)
+				i++
+				continue
+			}
+			if j < len(b) && b[j].start <= end {
+				group = append(group, b[j])
+				end = max(end, b[j].end)
+				j++
+				continue
+			}
+			break
+		}
+
+		var sides [2][]lineEdit
+		for _, e := range group {
+			sides[e.side] = append(sides[e.side], e)
+		}
+
+		out = append(out, baseLines[pos:start]...)
+		switch {
+		case len(sides[1]) == 0:
+			out = append(out, applyEdits(baseLines, start, end, sides[0])...)
+		case len(sides[0]) == 0:
+			out = append(out, applyEdits(baseLines, start, end, sides[1])...)
+		default:
+			oursRegion := applyEdits(baseLines, start, end, sides[0])
+			theirsRegion := applyEdits(baseLines, start, end, sides[1])
+			if strings.Join(oursRegion, "") != strings.Join(theirsRegion, "") {
+				return "", false
+			}
+			out = append(out, oursRegion...)
+		}
+		pos = end
+	}
+	out = append(out, baseLines[pos:]...)
+
+	return strings.Join(out, ""), true
+}
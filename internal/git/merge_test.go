@@ -0,0 +1,35 @@
+package git
+
+import "testing"
+
+func TestMergeText(t *testing.T) {
+	base := "a\nb\nc\nd\ne\n"
+
+	tests := []struct {
+		name   string
+		ours   string
+		theirs string
+		want   string
+		ok     bool
+	}{
+		{"only ours", "a\nB\nc\nd\ne\n", base, "a\nB\nc\nd\ne\n", true},
+		{"only theirs", base, "a\nb\nc\nD\ne\n", "a\nb\nc\nD\ne\n", true},
+		{"separate lines", "A\nb\nc\nd\ne\n", "a\nb\nc\nd\nE\n", "A\nb\nc\nd\nE\n", true},
+		{"same change", "a\nX\nc\nd\ne\n", "a\nX\nc\nd\ne\n", "a\nX\nc\nd\ne\n", true},
+		{"same line differently", "a\nX\nc\nd\ne\n", "a\nY\nc\nd\ne\n", "", false},
+		{"adjacent lines", "a\nX\nc\nd\ne\n", "a\nb\nY\nd\ne\n", "", false},
+		{"insert and delete apart", "start\na\nb\nc\nd\ne\n", "a\nb\nc\nd\n", "start\na\nb\nc\nd\n", true},
+		{"no trailing newline", "a\nb\nc\nd\ne", "A\nb\nc\nd\ne\n", "A\nb\nc\nd\ne", true},
+	}
+
+	for _, tt := range tests {
+		got, ok := mergeText(base, tt.ours, tt.theirs)
+		if ok != tt.ok {
+			t.Errorf("%s: mergeText() ok = %v; want %v", tt.name, ok, tt.ok)
+			continue
+		}
+		if ok && got != tt.want {
+			t.Errorf("%s: mergeText() = %q; want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package git
+
+import (
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// stageMerged is the stage of a normal index entry. go-git's index.Merged
+// constant is 1, which collides with the ancestor stage of a conflict.
+const stageMerged index.Stage = 0
+
+// treeFile is a blob placed at a path, the flat form used to build trees
+type treeFile struct {
+	Hash plumbing.Hash
+	Mode filemode.FileMode
+}
+
+// blobContent reads the full content of a blob
+func blobContent(r *git.Repository, hash plumbing.Hash) ([]byte, error) {
+	blob, err := r.BlobObject(hash)
+	if err != nil {
+		return nil, err
+	}
+	rd, err := blob.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	return io.ReadAll(rd)
+}
+
+// writeBlob stores content as a blob object
+func writeBlob(r *git.Repository, content []byte) (plumbing.Hash, error) {
+	obj := r.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	obj.SetSize(int64(len(content)))
+	w, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := w.Write(content); err != nil {
+		w.Close()
+		return plumbing.ZeroHash, err
+	}
+	if err := w.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return r.Storer.SetEncodedObject(obj)
+}
+
+// treeFiles flattens a tree into path -> blob
+func treeFiles(t *object.Tree) (map[string]treeFile, error) {
+	files := make(map[string]treeFile)
+	walker := object.NewTreeWalker(t, true, nil)
+	defer walker.Close()
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if entry.Mode == filemode.Dir {
+			continue
+		}
+		files[name] = treeFile{Hash: entry.Hash, Mode: entry.Mode}
+	}
+	return files, nil
+}
+
+// indexFiles returns the merged (stage 0) entries of the index as path -> blob
+func indexFiles(idx *index.Index) map[string]treeFile {
+	files := make(map[string]treeFile, len(idx.Entries))
+	for _, e := range idx.Entries {
+		if e.Stage != stageMerged {
+			continue
+		}
+		files[e.Name] = treeFile{Hash: e.Hash, Mode: e.Mode}
+	}
+	return files
+}
+
+// writeTree stores the nested tree objects for a flat set of files and
+// returns the hash of the root tree
+func writeTree(r *git.Repository, files map[string]treeFile) (plumbing.Hash, error) {
+	dirs := map[string][]object.TreeEntry{"": nil}
+	var dirNames []string
+
+	for name, f := range files {
+		dir := parentDir(name)
+		dirs[dir] = append(dirs[dir], object.TreeEntry{Name: path.Base(name), Mode: f.Mode, Hash: f.Hash})
+
+		// Register the ancestors too, so intermediate levels get a tree
+		for d := parentDir(dir); d != ""; d = parentDir(d) {
+			if _, ok := dirs[d]; ok {
+				break
+			}
+			dirs[d] = nil
+		}
+	}
+	for dir := range dirs {
+		if dir != "" {
+			dirNames = append(dirNames, dir)
+		}
+	}
+
+	// Deepest directories first so children are hashed before their parents
+	sort.Slice(dirNames, func(i, j int) bool {
+		di, dj := strings.Count(dirNames[i], "/"), strings.Count(dirNames[j], "/")
+		if di != dj {
+			return di > dj
+		}
+		return dirNames[i] < dirNames[j]
+	})
+
+	for _, dir := range append(dirNames, "") {
+		hash, err := storeTree(r, dirs[dir])
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		if dir == "" {
+			return hash, nil
+		}
+		parent := parentDir(dir)
+		dirs[parent] = append(dirs[parent], object.TreeEntry{Name: path.Base(dir), Mode: filemode.Dir, Hash: hash})
+	}
+
+	return plumbing.ZeroHash, nil
+}
+
+// parentDir returns the directory of a slash separated path, "" for the root
+func parentDir(name string) string {
+	dir := path.Dir(name)
+	if dir == "." || dir == "/" {
+		return ""
+	}
+	return dir
+}
+
+func storeTree(r *git.Repository, entries []object.TreeEntry) (plumbing.Hash, error) {
+	// Git orders tree entries as if directory names had a trailing slash
+	sortName := func(e object.TreeEntry) string {
+		if e.Mode == filemode.Dir {
+			return e.Name + "/"
+		}
+		return e.Name
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return sortName(entries[i]) < sortName(entries[j])
+	})
+
+	t := &object.Tree{Entries: entries}
+	obj := r.Storer.NewEncodedObject()
+	if err := t.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return r.Storer.SetEncodedObject(obj)
+}
+
+// writeCommit stores a commit object without moving any reference
+func writeCommit(r *git.Repository, tree plumbing.Hash, parents []plumbing.Hash, sig object.Signature, msg string) (plumbing.Hash, error) {
+	c := &object.Commit{
+		Author:       sig,
+		Committer:    sig,
+		Message:      msg,
+		TreeHash:     tree,
+		ParentHashes: parents,
+	}
+	obj := r.Storer.NewEncodedObject()
+	if err := c.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return r.Storer.SetEncodedObject(obj)
+}
+
+// currentSignature builds a signature from user.name/user.email, looking at
+// the repository config first and the global config second
+func currentSignature(r *git.Repository) object.Signature {
+	sig := object.Signature{Name: "gitdash", Email: "gitdash@localhost"}
+
+	id, _ := Identity(r)
+	if id.Name != "" {
+		sig.Name = id.Name
+	}
+	if id.Email != "" {
+		sig.Email = id.Email
+	}
+
+	sig.When = time.Now()
+	return sig
+}
+
+// commitSubject returns the first line of a commit message
+func commitSubject(msg string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+	return line
+}
+
+// blobHash computes the object id content would have as a blob
+func blobHash(content []byte) plumbing.Hash {
+	return plumbing.ComputeHash(plumbing.BlobObject, content)
+}
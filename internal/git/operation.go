@@ -0,0 +1,156 @@
+package git
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// Kinds of Operation, as git's prompt names them
+const (
+	OpMerge      = "MERGING"
+	OpRebase     = "REBASE"
+	OpAm         = "AM"
+	OpCherryPick = "CHERRY-PICKING"
+	OpRevert     = "REVERTING"
+	OpBisect     = "BISECTING"
+)
+
+// Operation is a multi-step git command that stopped halfway, waiting for
+// the user to continue or abort it
+type Operation struct {
+	Kind  string
+	Step  int // Rebase/am progress as Step of Total, 0 when unknown
+	Total int
+	Onto  string // Rebase target, a branch name when one points at it
+	Ref   string // Rebased branch, merged/picked/reverted commit, or where bisect started
+}
+
+// String renders the operation for a banner, e.g. "REBASE 3/7 onto main"
+func (o Operation) String() string {
+	s := o.Kind
+	if o.Total > 0 {
+		s += fmt.Sprintf(" %d/%d", o.Step, o.Total)
+	}
+	switch {
+	case o.Onto != "":
+		s += " onto " + o.Onto
+	case o.Ref != "":
+		s += " " + o.Ref
+	}
+	return s
+}
+
+// DetectOperations finds the operations in progress from the state files
+// git leaves in the git directory. Bisecting can overlap with the others,
+// so there may be more than one.
+func DetectOperations(r *git.Repository) ([]Operation, error) {
+	fs, err := gitDirFS(r)
+	if err != nil {
+		return nil, err
+	}
+	names := refNamesByHash(r)
+
+	var ops []Operation
+	for _, dir := range []string{"rebase-merge", "rebase-apply"} {
+		if _, err := fs.Stat(dir); err != nil {
+			continue
+		}
+		op := Operation{Kind: OpRebase}
+		if dir == "rebase-merge" {
+			op.Step, op.Total = stateInt(fs, dir+"/msgnum"), stateInt(fs, dir+"/end")
+		} else {
+			op.Step, op.Total = stateInt(fs, dir+"/next"), stateInt(fs, dir+"/last")
+			if _, err := fs.Stat(dir + "/applying"); err == nil {
+				op.Kind = OpAm
+			}
+		}
+		if onto := stateFile(fs, dir+"/onto"); onto != "" {
+			op.Onto = commitName(onto, names)
+		}
+		op.Ref = strings.TrimPrefix(stateFile(fs, dir+"/head-name"), "refs/heads/")
+		ops = append(ops, op)
+	}
+
+	for _, s := range []struct{ file, kind string }{
+		{"MERGE_HEAD", OpMerge},
+		{"CHERRY_PICK_HEAD", OpCherryPick},
+		{"REVERT_HEAD", OpRevert},
+	} {
+		if head := stateFile(fs, s.file); head != "" {
+			// An octopus merge lists one head per line
+			hash, _, _ := strings.Cut(head, "\n")
+			ops = append(ops, Operation{Kind: s.kind, Ref: commitName(hash, names)})
+		}
+	}
+
+	if _, err := fs.Stat("BISECT_LOG"); err == nil {
+		ops = append(ops, Operation{Kind: OpBisect, Ref: stateFile(fs, "BISECT_START")})
+	}
+	return ops, nil
+}
+
+// stateFile reads a file from the git directory without its trailing
+// newline, or returns "" when it does not exist
+func stateFile(fs billy.Filesystem, name string) string {
+	f, err := fs.Open(name)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func stateInt(fs billy.Filesystem, name string) int {
+	n, _ := strconv.Atoi(stateFile(fs, name))
+	return n
+}
+
+// refNamesByHash maps commits to the branch names pointing at them, local
+// branches before remote ones
+func refNamesByHash(r *git.Repository) map[string][]string {
+	local, remote := map[string][]string{}, map[string][]string{}
+	if refs, err := r.References(); err == nil {
+		_ = refs.ForEach(func(ref *plumbing.Reference) error {
+			hash := ref.Hash().String()
+			switch {
+			case ref.Type() != plumbing.HashReference:
+			case ref.Name().IsBranch():
+				local[hash] = append(local[hash], ref.Name().Short())
+			case ref.Name().IsRemote():
+				remote[hash] = append(remote[hash], ref.Name().Short())
+			}
+			return nil
+		})
+	}
+
+	names := map[string][]string{}
+	for _, m := range []map[string][]string{local, remote} {
+		for hash, n := range m {
+			sort.Strings(n)
+			names[hash] = append(names[hash], n...)
+		}
+	}
+	return names
+}
+
+// commitName names a commit by a branch pointing at it, or its short hash
+func commitName(hash string, names map[string][]string) string {
+	if n := names[hash]; len(n) > 0 {
+		return n[0]
+	}
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
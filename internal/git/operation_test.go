@@ -0,0 +1,69 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestDetectOperations(t *testing.T) {
+	r, dir := newTestRepo(t)
+	cfg, err := r.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.User.Name, cfg.User.Email = "Ada", "ada@example.com"
+	if err := r.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	base := commitFile(t, r, dir, "a.txt", "a\n", "a")
+	if err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), base)); err != nil {
+		t.Fatal(err)
+	}
+	commitFile(t, r, dir, "b.txt", "b\n", "b")
+
+	detect := func() []string {
+		t.Helper()
+		ops, err := DetectOperations(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, op := range ops {
+			names = append(names, op.String())
+		}
+		return names
+	}
+	writeFile(t, dir, ".git/rebase-merge/msgnum", "3\n")
+	writeFile(t, dir, ".git/rebase-merge/end", "7\n")
+	writeFile(t, dir, ".git/rebase-merge/onto", base.String()+"\n")
+	writeFile(t, dir, ".git/rebase-merge/head-name", "refs/heads/feature\n")
+	writeFile(t, dir, ".git/BISECT_LOG", "")
+	writeFile(t, dir, ".git/BISECT_START", "master\n")
+	if got := detect(); len(got) != 2 || got[0] != "REBASE 3/7 onto main" || got[1] != "BISECTING master" {
+		t.Errorf("operations = %q", got)
+	}
+	for _, name := range []string{"rebase-merge", "BISECT_LOG", "BISECT_START"} {
+		if err := os.RemoveAll(filepath.Join(dir, ".git", name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeFile(t, dir, ".git/rebase-apply/next", "1\n")
+	writeFile(t, dir, ".git/rebase-apply/last", "2\n")
+	writeFile(t, dir, ".git/rebase-apply/applying", "")
+	if got := detect(); len(got) != 1 || got[0] != "AM 1/2" {
+		t.Errorf("operations = %q", got)
+	}
+	if err := os.RemoveAll(filepath.Join(dir, ".git", "rebase-apply")); err != nil {
+		t.Fatal(err)
+	}
+
+	writeFile(t, dir, ".git/MERGE_HEAD", base.String()+"\n")
+	if got := detect(); len(got) != 1 || got[0] != "MERGING main" {
+		t.Errorf("operations = %q", got)
+	}
+}
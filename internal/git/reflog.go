@@ -0,0 +1,160 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// ReflogEntry is a single line of a reference log
+type ReflogEntry struct {
+	Old       plumbing.Hash
+	New       plumbing.Hash
+	Committer object.Signature
+	Message   string
+}
+
+// gitDirFS returns the filesystem rooted at the .git directory.
+// go-git does not expose reflogs, so we read them from disk ourselves.
+func gitDirFS(r *git.Repository) (billy.Filesystem, error) {
+	s, ok := r.Storer.(*filesystem.Storage)
+	if !ok {
+		return nil, errors.New("repository is not backed by a filesystem")
+	}
+	return s.Filesystem(), nil
+}
+
+// readReflog returns the reflog of the given reference, newest entry first.
+// A missing reflog is not an error, it simply yields no entries.
+func readReflog(r *git.Repository, name plumbing.ReferenceName) ([]ReflogEntry, error) {
+	fs, err := gitDirFS(r)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := fs.Open(path.Join("logs", name.String()))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []ReflogEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry, ok := parseReflogLine(scanner.Text())
+		if !ok {
+			continue // Skip malformed lines instead of failing the whole log
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// The file is oldest first, callers want the most recent entry at index 0
+	for i, j := 0, len(entries)-1; i < j; i, j = i+1, j-1 {
+		entries[i], entries[j] = entries[j], entries[i]
+	}
+
+	return entries, nil
+}
+
+// parseReflogLine parses "<old> <new> <name> <<email>> <time> <tz>\t<message>"
+func parseReflogLine(line string) (ReflogEntry, bool) {
+	header, message, _ := strings.Cut(line, "\t")
+	if len(header) < 82 || header[40] != ' ' || header[81] != ' ' {
+		return ReflogEntry{}, false
+	}
+
+	oldHash, newHash := header[:40], header[41:81]
+	if !plumbing.IsHash(oldHash) || !plumbing.IsHash(newHash) {
+		return ReflogEntry{}, false
+	}
+
+	var sig object.Signature
+	sig.Decode([]byte(header[82:]))
+
+	return ReflogEntry{
+		Old:       plumbing.NewHash(oldHash),
+		New:       plumbing.NewHash(newHash),
+		Committer: sig,
+		Message:   message,
+	}, true
+}
+
+// formatReflogLine renders an entry the way git writes it to logs/
+func formatReflogLine(e ReflogEntry) string {
+	var sig strings.Builder
+	e.Committer.Encode(&sig)
+	return e.Old.String() + " " + e.New.String() + " " + sig.String() + "\t" + e.Message + "\n"
+}
+
+// appendReflog adds an entry at the end (newest position) of a reference log
+func appendReflog(r *git.Repository, name plumbing.ReferenceName, e ReflogEntry) error {
+	fs, err := gitDirFS(r)
+	if err != nil {
+		return err
+	}
+
+	logPath := path.Join("logs", name.String())
+	if err := fs.MkdirAll(path.Dir(logPath), 0755); err != nil {
+		return err
+	}
+
+	f, err := fs.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write([]byte(formatReflogLine(e))); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// writeReflog replaces a reference log; entries are given newest first.
+// An empty list removes the log.
+func writeReflog(r *git.Repository, name plumbing.ReferenceName, entries []ReflogEntry) error {
+	fs, err := gitDirFS(r)
+	if err != nil {
+		return err
+	}
+
+	logPath := path.Join("logs", name.String())
+	if len(entries) == 0 {
+		if err := fs.Remove(logPath); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	var s strings.Builder
+	for i := len(entries) - 1; i >= 0; i-- {
+		s.WriteString(formatReflogLine(entries[i]))
+	}
+
+	if err := fs.MkdirAll(path.Dir(logPath), 0755); err != nil {
+		return err
+	}
+	f, err := fs.OpenFile(logPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write([]byte(s.String())); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
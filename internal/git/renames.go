@@ -0,0 +1,112 @@
+package git
+
+import (
+	"sort"
+
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// DefaultRenameThreshold is the similarity git requires of a rename by default
+const DefaultRenameThreshold = 50
+
+// maxRenamePairs caps the file pairs compared by content. Past it detection
+// is skipped, much like git gives up past diff.renameLimit.
+const maxRenamePairs = 100 * 100
+
+// RenameOptions controls rename and copy detection in the working directory status
+type RenameOptions struct {
+	Threshold int  // Minimum similarity in percent, 0 turns detection off
+	Copies    bool // Also pair new files with the modified files they were copied from
+}
+
+// renameFile is one side of a possible rename: a deleted or modified file
+// as it was, or a new file as it is now
+type renameFile struct {
+	path    string
+	hash    plumbing.Hash
+	content []byte
+}
+
+// renameMatch pairs a new file with the file it was renamed or copied from
+type renameMatch struct {
+	from, to   string
+	similarity int
+	copy       bool
+}
+
+// similarity is how much of the larger file is unchanged lines of the
+// other, in percent. Only identical content scores 100.
+func similarity(a, b renameFile) int {
+	if a.hash == b.hash {
+		return 100
+	}
+	if isBinary(a.content) || isBinary(b.content) {
+		return 0
+	}
+	larger := max(len(a.content), len(b.content))
+	common := 0
+	for _, l := range textDiffLines(a.content, b.content) {
+		if l.Kind == DiffContext {
+			common += len(l.Text) + 1
+		}
+	}
+	return min(common*100/larger, 99)
+}
+
+// detectRenames pairs new files with deleted ones, and with modified ones
+// when copies are wanted, best matches first. A deleted file is renamed
+// once; any further matches of it are copies.
+func detectRenames(deleted, modified, added []renameFile, opts RenameOptions) []renameMatch {
+	sources := deleted
+	if opts.Copies {
+		sources = append(sources[:len(sources):len(sources)], modified...)
+	}
+	if opts.Threshold <= 0 || len(sources) == 0 || len(added) == 0 || len(sources)*len(added) > maxRenamePairs {
+		return nil
+	}
+
+	type pair struct{ src, dst, score int }
+	var pairs []pair
+	for di, d := range added {
+		for si, s := range sources {
+			// Unchanged lines can make up at most the smaller file
+			if s.hash != d.hash && min(len(s.content), len(d.content))*100 < opts.Threshold*max(len(s.content), len(d.content)) {
+				continue
+			}
+			if score := similarity(s, d); score >= opts.Threshold {
+				pairs = append(pairs, pair{si, di, score})
+			}
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].score != pairs[j].score {
+			return pairs[i].score > pairs[j].score
+		}
+		if pairs[i].dst != pairs[j].dst {
+			return added[pairs[i].dst].path < added[pairs[j].dst].path
+		}
+		return sources[pairs[i].src].path < sources[pairs[j].src].path
+	})
+
+	var matches []renameMatch
+	matched := make(map[int]bool)
+	renamed := make(map[int]bool)
+	for _, p := range pairs {
+		if matched[p.dst] {
+			continue
+		}
+		copied := p.src >= len(deleted) || renamed[p.src]
+		if copied && !opts.Copies {
+			continue
+		}
+		matched[p.dst] = true
+		renamed[p.src] = renamed[p.src] || !copied
+		matches = append(matches, renameMatch{
+			from:       sources[p.src].path,
+			to:         added[p.dst].path,
+			similarity: p.score,
+			copy:       copied,
+		})
+	}
+	return matches
+}
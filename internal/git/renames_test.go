@@ -0,0 +1,68 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenameStatus(t *testing.T) {
+	r, dir := newTestRepo(t)
+	ten := strings.Join(numbered(10), "")
+	commitFile(t, r, dir, "a.txt", ten, "a")
+	commitFile(t, r, dir, "b.txt", strings.Join(numbered(20), ""), "b")
+	commitFile(t, r, dir, "keep.txt", "keep\n"+ten, "keep")
+	commitFile(t, r, dir, "empty.txt", "", "empty")
+
+	remove := func(name string) {
+		t.Helper()
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A staged move, an unstaged move with one line of twenty changed, an
+	// unrelated new file and a moved empty file
+	remove("a.txt")
+	writeFile(t, dir, "moved/a.txt", ten)
+	if err := StagePaths(r, "a.txt", "moved"); err != nil {
+		t.Fatal(err)
+	}
+	remove("b.txt")
+	lines := numbered(20)
+	lines[9] = "changed\n"
+	writeFile(t, dir, "b2.txt", strings.Join(lines, ""))
+	writeFile(t, dir, "c.txt", "something else\n")
+	remove("empty.txt")
+	writeFile(t, dir, "empty2.txt", "")
+	// A copy of keep.txt, which is modified too
+	writeFile(t, dir, "keep2.txt", "keep\n"+ten)
+	writeFile(t, dir, "keep.txt", "kept\n"+ten)
+
+	want := "b.txt>b2.txt:R94 c.txt:? empty.txt:D empty2.txt:? keep.txt:M keep2.txt:? +a.txt>moved/a.txt:R100"
+	if got := statusRows(t, r); got != want {
+		t.Errorf("status = %s, want %s", got, want)
+	}
+	want = "b.txt>b2.txt:R94 c.txt:? empty.txt:D empty2.txt:? keep.txt:M keep.txt>keep2.txt:C100 +a.txt>moved/a.txt:R100"
+	if got := statusRowsWith(t, r, RenameOptions{Threshold: DefaultRenameThreshold, Copies: true}); got != want {
+		t.Errorf("status with copies = %s, want %s", got, want)
+	}
+	want = "b.txt:D b2.txt:? c.txt:? empty.txt:D empty2.txt:? keep.txt:M keep2.txt:? +a.txt>moved/a.txt:R100"
+	if got := statusRowsWith(t, r, RenameOptions{Threshold: 95}); got != want {
+		t.Errorf("status at 95%% = %s, want %s", got, want)
+	}
+	want = "+a.txt:D b.txt:D b2.txt:? c.txt:? empty.txt:D empty2.txt:? keep.txt:M keep2.txt:? +moved/a.txt:A"
+	if got := statusRowsWith(t, r, RenameOptions{}); got != want {
+		t.Errorf("status without detection = %s, want %s", got, want)
+	}
+
+	ws, err := GetWorkingDirStatus(r, RenameOptions{Threshold: DefaultRenameThreshold, Copies: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ws.Renamed != 2 || ws.Copied != 1 || ws.Staged != 0 || ws.Modified != 2 || ws.Untracked != 2 {
+		t.Errorf("counts = %d renamed, %d copied, %d staged, %d modified, %d untracked",
+			ws.Renamed, ws.Copied, ws.Staged, ws.Modified, ws.Untracked)
+	}
+}
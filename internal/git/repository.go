@@ -13,9 +13,12 @@ type Repository = git.Repository
 
 type RepoInfo struct {
 	Path          string
-	CurrentBranch string
+	CurrentBranch string // Branch name, or the short hash when detached
+	HeadHash      string
+	Detached      bool
 	IsClean       bool
 	Remotes       []string
+	Operations    []Operation // Merge, rebase etc. stopped halfway
 	Repo          *git.Repository
 }
 
@@ -23,11 +26,15 @@ func GetRepoInfoFromRepo(path string, r *git.Repository) (*RepoInfo, error) {
 	// Get current branch
 	headRef, err := r.Head()
 	currentBranch := ""
+	headHash := ""
+	detached := false
 	if err == nil {
+		headHash = headRef.Hash().String()
 		if headRef.Name().IsBranch() {
 			currentBranch = headRef.Name().Short()
 		} else {
-			currentBranch = headRef.Hash().String()[:7] // Detached head
+			currentBranch = headHash[:7] // Detached head
+			detached = true
 		}
 	} else if err == plumbing.ErrReferenceNotFound {
 		currentBranch = "Empty Repository"
@@ -56,11 +63,17 @@ func GetRepoInfoFromRepo(path string, r *git.Repository) (*RepoInfo, error) {
 		}
 	}
 
+	// A missing git directory filesystem just means nothing is in progress
+	operations, _ := DetectOperations(r)
+
 	return &RepoInfo{
 		Path:          path,
 		CurrentBranch: currentBranch,
+		HeadHash:      headHash,
+		Detached:      detached,
 		IsClean:       isClean,
 		Remotes:       remoteURLs,
+		Operations:    operations,
 		Repo:          r,
 	}, nil
 }
@@ -4,6 +4,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
 func TestFindRepo(t *testing.T) {
@@ -64,3 +69,51 @@ func TestFindRepo(t *testing.T) {
 		t.Error("FindRepo(nonGitDir) should fail")
 	}
 }
+
+// newTestRepo initialises an empty repository in a temp dir
+func newTestRepo(t *testing.T) (*git.Repository, string) {
+	t.Helper()
+	dir := t.TempDir()
+	r, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r, dir
+}
+
+var testSignature = object.Signature{
+	Name:  "Test",
+	Email: "test@example.com",
+	When:  time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+}
+
+// writeFile writes content to a path relative to the worktree root
+func writeFile(t *testing.T, dir, name, content string) {
+	t.Helper()
+	full := filepath.Join(dir, name)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// commitFile writes and stages a file, then commits it on HEAD
+func commitFile(t *testing.T, r *git.Repository, dir, name, content, msg string) plumbing.Hash {
+	t.Helper()
+	writeFile(t, dir, name, content)
+	w, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Add(name); err != nil {
+		t.Fatal(err)
+	}
+	sig := testSignature
+	hash, err := w.Commit(msg, &git.CommitOptions{Author: &sig, Committer: &sig})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
@@ -0,0 +1,170 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// refPrefixes are tried in order when resolving a short ref name. Local
+// branches come first because that is what the dashboard mostly inspects;
+// plain git would prefer a tag of the same name.
+var refPrefixes = []string{
+	"refs/heads/",
+	"refs/remotes/",
+	"refs/tags/",
+	"",
+}
+
+// ResolveRevision resolves a revision to the commit it names. It accepts
+// branch names, remote refs (origin/main), tags, full refs, full or
+// abbreviated hashes, HEAD, and the suffixes ~N, ^N, ^{}, ^{commit} and
+// ^{/regex}, e.g. HEAD~3 or main^2. An empty revision means HEAD.
+func ResolveRevision(r *git.Repository, rev string) (plumbing.Hash, error) {
+	// Ref names cannot contain ~, ^, : or @{, so everything from the first
+	// operator on is a suffix applied to the base name
+	base, suffix := rev, ""
+	i := strings.IndexAny(rev, "~^:")
+	if j := strings.Index(rev, "@{"); j >= 0 && (i < 0 || j < i) {
+		i = j
+	}
+	if i >= 0 {
+		base, suffix = rev[:i], rev[i:]
+	}
+
+	c, err := resolveBase(r, base)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("unknown revision %q: %w", rev, err)
+	}
+
+	for suffix != "" {
+		op := suffix[0]
+		suffix = suffix[1:]
+
+		switch op {
+		case '~':
+			var n int
+			n, suffix = leadingNumber(suffix)
+			for ; n > 0; n-- {
+				if c, err = nthParent(r, c, 1, rev); err != nil {
+					return plumbing.ZeroHash, err
+				}
+			}
+		case '^':
+			if strings.HasPrefix(suffix, "{") {
+				end := strings.IndexByte(suffix, '}')
+				if end < 0 {
+					return plumbing.ZeroHash, fmt.Errorf("invalid revision %q: missing }", rev)
+				}
+				inner := suffix[1:end]
+				suffix = suffix[end+1:]
+
+				switch {
+				case inner == "" || inner == "commit":
+					// Already peeled to a commit
+				case strings.HasPrefix(inner, "/"):
+					if c, err = searchMessage(c, inner[1:]); err != nil {
+						return plumbing.ZeroHash, fmt.Errorf("invalid revision %q: %w", rev, err)
+					}
+				default:
+					return plumbing.ZeroHash, fmt.Errorf("unsupported revision %q: only commits can be inspected", rev)
+				}
+				continue
+			}
+
+			var n int
+			n, suffix = leadingNumber(suffix)
+			if n > 0 {
+				if c, err = nthParent(r, c, n, rev); err != nil {
+					return plumbing.ZeroHash, err
+				}
+			}
+		default:
+			return plumbing.ZeroHash, fmt.Errorf("unsupported revision %q", rev)
+		}
+	}
+
+	return c.Hash, nil
+}
+
+// resolveBase resolves the part of a revision before any suffix
+func resolveBase(r *git.Repository, name string) (*object.Commit, error) {
+	if name == "" || name == "@" {
+		name = "HEAD"
+	}
+
+	for _, prefix := range refPrefixes {
+		ref, err := r.Reference(plumbing.ReferenceName(prefix+name), true)
+		if err == plumbing.ErrReferenceNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return peelCommit(r, ref.Hash())
+	}
+
+	// Not a ref, so let go-git try it as a full or abbreviated hash
+	if h, err := r.ResolveRevision(plumbing.Revision(name)); err == nil {
+		return peelCommit(r, *h)
+	}
+	return nil, plumbing.ErrReferenceNotFound
+}
+
+// peelCommit follows annotated tags down to the commit they point at
+func peelCommit(r *git.Repository, h plumbing.Hash) (*object.Commit, error) {
+	if tag, err := r.TagObject(h); err == nil {
+		return tag.Commit()
+	}
+	return r.CommitObject(h)
+}
+
+// leadingNumber splits an optional count off s; a missing count means 1
+func leadingNumber(s string) (int, string) {
+	i := 0
+	for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	if i == 0 {
+		return 1, s
+	}
+	n, _ := strconv.Atoi(s[:i])
+	return n, s[i:]
+}
+
+func nthParent(r *git.Repository, c *object.Commit, n int, rev string) (*object.Commit, error) {
+	if n > len(c.ParentHashes) {
+		return nil, fmt.Errorf("invalid revision %q: %s has no parent %d", rev, c.Hash.String()[:7], n)
+	}
+	return r.CommitObject(c.ParentHashes[n-1])
+}
+
+// searchMessage finds the newest commit reachable from c whose message matches pattern
+func searchMessage(c *object.Commit, pattern string) (*object.Commit, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *object.Commit
+	err = object.NewCommitPreorderIter(c, nil, nil).ForEach(func(hc *object.Commit) error {
+		if re.MatchString(hc.Message) {
+			found = hc
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no commit message matches %q", pattern)
+	}
+	return found, nil
+}
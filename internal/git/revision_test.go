@@ -0,0 +1,79 @@
+package git
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestResolveRevision(t *testing.T) {
+	r, dir := newTestRepo(t)
+	first := commitFile(t, r, dir, "a.txt", "1\n", "first")
+	second := commitFile(t, r, dir, "a.txt", "2\n", "second: fix parser")
+	side := commitFile(t, r, dir, "b.txt", "side\n", "side work")
+
+	// A merge of second and side, with second as the first parent
+	w, _ := r.Worktree()
+	sig := testSignature
+	merge, err := w.Commit("merge side", &git.CommitOptions{
+		Author:    &sig,
+		Committer: &sig,
+		Parents:   []plumbing.Hash{second, side},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := r.CreateTag("v1", first, &git.CreateTagOptions{Tagger: &sig, Message: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	// A tag named like a branch must not shadow the branch
+	if _, err := r.CreateTag("master", first, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Storer.SetReference(plumbing.NewHashReference("refs/remotes/origin/main", side)); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rev  string
+		want plumbing.Hash
+	}{
+		{"", merge},
+		{"HEAD", merge},
+		{"@", merge},
+		{"master", merge},
+		{"refs/tags/master", first},
+		{"v1", first},
+		{"v1^{}", first},
+		{"origin/main", side},
+		{merge.String(), merge},
+		{side.String()[:7], side},
+		{"HEAD~1", second},
+		{"HEAD~2", first},
+		{"HEAD~", second},
+		{"master^2", side},
+		{"master^2~1", second},
+		{"HEAD^0", merge},
+		{"HEAD^{commit}", merge},
+		{"HEAD^{/fix}", second},
+	}
+
+	for _, tt := range tests {
+		got, err := ResolveRevision(r, tt.rev)
+		if err != nil {
+			t.Errorf("ResolveRevision(%q) error: %v", tt.rev, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ResolveRevision(%q) = %s; want %s", tt.rev, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"nope", "HEAD~9", "master^3", "HEAD^{tree}", "HEAD@{1}", "HEAD:a.txt", "HEAD^{/nomatch}"} {
+		if h, err := ResolveRevision(r, bad); err == nil {
+			t.Errorf("ResolveRevision(%q) = %s; want an error", bad, h)
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package git
+
+import (
+	"container/heap"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	paintA uint8 = 1 << iota
+	paintB
+)
+
+// commitHeap pops the most recently committed commit first
+type commitHeap []*object.Commit
+
+func (h commitHeap) Len() int { return len(h) }
+func (h commitHeap) Less(i, j int) bool {
+	return h[i].Committer.When.After(h[j].Committer.When)
+}
+func (h commitHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *commitHeap) Push(x any)   { *h = append(*h, x.(*object.Commit)) }
+func (h *commitHeap) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
+
+// walkByDate visits the commits reachable from "from" newest committed
+// first, like `git log`. A commit is never visited before the child it was
+// reached from. fn returns false to stop the walk.
+func walkByDate(r *git.Repository, from plumbing.Hash, fn func(c *object.Commit) (bool, error)) error {
+	start, err := r.CommitObject(from)
+	if err != nil {
+		return err
+	}
+
+	seen := map[plumbing.Hash]bool{from: true}
+	queue := &commitHeap{start}
+
+	for queue.Len() > 0 {
+		c := heap.Pop(queue).(*object.Commit)
+		more, err := fn(c)
+		if err != nil || !more {
+			return err
+		}
+
+		for _, p := range c.ParentHashes {
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+			pc, err := r.CommitObject(p)
+			if err == plumbing.ErrObjectNotFound {
+				continue // Shallow boundary
+			}
+			if err != nil {
+				return err
+			}
+			heap.Push(queue, pc)
+		}
+	}
+	return nil
+}
+
+// walkTopo visits the commits reachable from "from" with every commit after
+// all of its children, newest committed first among those that are ready,
+// like `git log --date-order`. Unlike walkByDate this holds with skewed
+// commit dates, at the cost of reading all of the history up front.
+// fn returns false to stop the walk.
+func walkTopo(r *git.Repository, from plumbing.Hash, fn func(c *object.Commit) (bool, error)) error {
+	start, err := r.CommitObject(from)
+	if err != nil {
+		return err
+	}
+
+	// Count the children of each reachable commit
+	commits := map[plumbing.Hash]*object.Commit{from: start}
+	children := make(map[plumbing.Hash]int)
+	stack := []*object.Commit{start}
+	for len(stack) > 0 {
+		c := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, p := range c.ParentHashes {
+			children[p]++
+			if _, ok := commits[p]; ok {
+				continue
+			}
+			pc, err := r.CommitObject(p)
+			if err == plumbing.ErrObjectNotFound {
+				continue // Shallow boundary
+			}
+			if err != nil {
+				return err
+			}
+			commits[p] = pc
+			stack = append(stack, pc)
+		}
+	}
+
+	ready := &commitHeap{start}
+	for ready.Len() > 0 {
+		c := heap.Pop(ready).(*object.Commit)
+		more, err := fn(c)
+		if err != nil || !more {
+			return err
+		}
+
+		for _, p := range c.ParentHashes {
+			children[p]--
+			if pc, ok := commits[p]; ok && children[p] == 0 {
+				heap.Push(ready, pc)
+			}
+		}
+	}
+	return nil
+}
+
+// paintCommits walks history from a and b newest first, tagging each commit
+// with the side(s) it is reachable from. The walk stops once every pending
+// commit is reachable from both sides, since their ancestors are shared too,
+// so only the commits that differ between a and b (plus the boundary) are read.
+// Several b tips paint the same side, e.g. every ref but a.
+func paintCommits(r *git.Repository, a plumbing.Hash, b ...plumbing.Hash) (map[plumbing.Hash]uint8, error) {
+	flags := make(map[plumbing.Hash]uint8)
+	queue := &commitHeap{}
+
+	push := func(h plumbing.Hash, f uint8) error {
+		old := flags[h]
+		if old|f == old {
+			return nil
+		}
+		flags[h] = old | f
+		c, err := r.CommitObject(h)
+		if err == plumbing.ErrObjectNotFound {
+			return nil // Shallow boundary
+		}
+		if err != nil {
+			return err
+		}
+		heap.Push(queue, c)
+		return nil
+	}
+
+	if err := push(a, paintA); err != nil {
+		return nil, err
+	}
+	for _, h := range b {
+		if err := push(h, paintB); err != nil {
+			return nil, err
+		}
+	}
+
+	for queue.Len() > 0 {
+		interesting := false
+		for _, c := range *queue {
+			if flags[c.Hash] != paintA|paintB {
+				interesting = true
+				break
+			}
+		}
+		if !interesting {
+			break
+		}
+
+		c := heap.Pop(queue).(*object.Commit)
+		f := flags[c.Hash]
+		for _, p := range c.ParentHashes {
+			if err := push(p, f); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// What is still queued is shared history. With equal or skewed commit
+	// times some of its ancestors may have been visited from one side only,
+	// so mark every visited ancestor of it as shared as well.
+	var stack []plumbing.Hash
+	for _, c := range *queue {
+		stack = append(stack, c.ParentHashes...)
+	}
+	for len(stack) > 0 {
+		h := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		f, visited := flags[h]
+		if !visited || f == paintA|paintB {
+			continue
+		}
+		flags[h] = paintA | paintB
+		c, err := r.CommitObject(h)
+		if err != nil {
+			continue
+		}
+		stack = append(stack, c.ParentHashes...)
+	}
+
+	return flags, nil
+}
+
+// aheadBehind counts the commits reachable only from a (ahead) and only from b (behind)
+func aheadBehind(r *git.Repository, a, b plumbing.Hash) (ahead, behind int, err error) {
+	if a == b {
+		return 0, 0, nil
+	}
+
+	flags, err := paintCommits(r, a, b)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, f := range flags {
+		switch f {
+		case paintA:
+			ahead++
+		case paintB:
+			behind++
+		}
+	}
+	return ahead, behind, nil
+}
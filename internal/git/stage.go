@@ -0,0 +1,332 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/utils/diff"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// matchesPathspec reports whether p is one of specs or lies below one of
+// them; no specs, an empty one or "." match everything
+func matchesPathspec(p string, specs []string) bool {
+	if len(specs) == 0 {
+		return true
+	}
+	for _, s := range specs {
+		s = strings.TrimSuffix(s, "/")
+		if s == "" || s == "." || p == s || strings.HasPrefix(p, s+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// StagePaths records the worktree state of the given files or directories
+// in the index, like `git add -A <paths>`: changes and new files are added,
+// deleted files are removed. Staging a conflicted file marks it resolved.
+func StagePaths(r *git.Repository, paths ...string) error {
+	w, err := r.Worktree()
+	if err != nil {
+		return err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return err
+	}
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return err
+	}
+
+	changed := map[string]bool{}
+	for p, s := range status {
+		if s.Worktree != git.Unmodified && matchesPathspec(p, paths) {
+			changed[p] = true
+		}
+	}
+	// Status misses unmerged paths whose worktree file matches a stage
+	for _, c := range indexConflicts(idx) {
+		if matchesPathspec(c.Path, paths) {
+			changed[c.Path] = true
+		}
+	}
+
+	for p := range changed {
+		content, mode, ok, err := readWorktreeFile(w, p)
+		if err != nil {
+			return err
+		}
+		removeIndexEntry(idx, p)
+		if !ok {
+			continue
+		}
+		hash, err := writeBlob(r, content)
+		if err != nil {
+			return err
+		}
+		setIndexEntry(idx, p, hash, mode, len(content))
+	}
+	return r.Storer.SetIndex(idx)
+}
+
+// UnstagePaths resets the index entries of the given files or directories
+// to HEAD, like `git restore --staged <paths>`. The worktree is untouched,
+// so staged new files become untracked again.
+func UnstagePaths(r *git.Repository, paths ...string) error {
+	headHash := plumbing.ZeroHash // Unborn branch, everything is new
+	if head, err := r.Head(); err == nil {
+		headHash = head.Hash()
+	}
+	headTree, err := commitTree(r, headHash)
+	if err != nil {
+		return err
+	}
+	headFiles, err := treeFiles(headTree)
+	if err != nil {
+		return err
+	}
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return err
+	}
+
+	candidates := map[string]bool{}
+	conflicted := map[string]bool{}
+	for _, e := range idx.Entries {
+		if matchesPathspec(e.Name, paths) {
+			candidates[e.Name] = true
+			conflicted[e.Name] = conflicted[e.Name] || e.Stage != stageMerged
+		}
+	}
+	for p := range headFiles {
+		if matchesPathspec(p, paths) {
+			candidates[p] = true
+		}
+	}
+
+	staged := indexFiles(idx)
+	for p := range candidates {
+		hf, inHead := headFiles[p]
+		if sf, inIndex := staged[p]; inIndex == inHead && sf == hf && !conflicted[p] {
+			continue
+		}
+		removeIndexEntry(idx, p)
+		if !inHead {
+			continue
+		}
+		blob, err := r.BlobObject(hf.Hash)
+		if err != nil {
+			return err
+		}
+		setIndexEntry(idx, p, hf.Hash, hf.Mode, int(blob.Size))
+	}
+	return r.Storer.SetIndex(idx)
+}
+
+// FileHunks is the diff of one file, split into what is staged and what is not
+type FileHunks struct {
+	Path     string
+	Unstaged FileDiff // Index to worktree
+	Staged   FileDiff // HEAD to index
+}
+
+// fileVersion is a file as HEAD, the index or the worktree has it
+type fileVersion struct {
+	content []byte
+	mode    filemode.FileMode
+	ok      bool // False when the file does not exist there
+}
+
+// fileVersions reads path from HEAD, the index and the worktree
+func fileVersions(r *git.Repository, path string) (head, staged, work fileVersion, idx *index.Index, err error) {
+	headHash := plumbing.ZeroHash // Unborn branch
+	if ref, err := r.Head(); err == nil {
+		headHash = ref.Hash()
+	}
+	headTree, err := commitTree(r, headHash)
+	if err != nil {
+		return head, staged, work, nil, err
+	}
+	if f, ok := treeEntry(headTree, path); ok {
+		head = fileVersion{mode: f.Mode, ok: true}
+		if head.content, err = blobContent(r, f.Hash); err != nil {
+			return head, staged, work, nil, err
+		}
+	}
+
+	idx, err = r.Storer.Index()
+	if err != nil {
+		return head, staged, work, nil, err
+	}
+	for _, e := range idx.Entries {
+		if e.Name != path {
+			continue
+		}
+		if e.Stage != stageMerged {
+			return head, staged, work, nil, fmt.Errorf("%s has unresolved conflicts", path)
+		}
+		staged = fileVersion{mode: e.Mode, ok: true}
+		if staged.content, err = blobContent(r, e.Hash); err != nil {
+			return head, staged, work, nil, err
+		}
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		return head, staged, work, nil, err
+	}
+	work.content, work.mode, work.ok, err = readWorktreeFile(w, path)
+	return head, staged, work, idx, err
+}
+
+// textDiff diffs two versions of a file line by line
+func textDiff(path string, from, to fileVersion, context int) FileDiff {
+	f := FileDiff{OldMode: from.mode, NewMode: to.mode}
+	if from.ok {
+		f.OldPath = path
+	}
+	if to.ok {
+		f.NewPath = path
+	}
+	if isBinary(from.content) || isBinary(to.content) {
+		f.Binary = true
+		return f
+	}
+	f.Hunks = buildHunks(textDiffLines(from.content, to.content), context)
+	return f
+}
+
+// lineChunk is a run of lines from a text diff, read like a patch chunk
+type lineChunk struct {
+	content string
+	op      fdiff.Operation
+}
+
+func (c lineChunk) Content() string       { return c.content }
+func (c lineChunk) Type() fdiff.Operation { return c.op }
+
+// textDiffLines numbers every line of the diff between two texts
+func textDiffLines(from, to []byte) []DiffLine {
+	var chunks []fdiff.Chunk
+	for _, d := range diff.Do(string(from), string(to)) {
+		op := fdiff.Equal
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = fdiff.Add
+		case diffmatchpatch.DiffDelete:
+			op = fdiff.Delete
+		}
+		chunks = append(chunks, lineChunk{content: d.Text, op: op})
+	}
+	return diffLines(chunks)
+}
+
+// GetFileHunks diffs one file between HEAD, the index and the worktree
+func GetFileHunks(r *git.Repository, path string) (*FileHunks, error) {
+	head, staged, work, _, err := fileVersions(r, path)
+	if err != nil {
+		return nil, err
+	}
+	return &FileHunks{
+		Path:     path,
+		Unstaged: textDiff(path, staged, work, DefaultContext),
+		Staged:   textDiff(path, head, staged, DefaultContext),
+	}, nil
+}
+
+// StageLines stages some of the changed lines of a file, like picking
+// hunks in `git add -p`: the index gets a blob with only those lines of the
+// index-to-worktree diff applied. lines come from FileHunks.Unstaged.
+func StageLines(r *git.Repository, path string, lines []DiffLine) error {
+	_, staged, work, idx, err := fileVersions(r, path)
+	if err != nil {
+		return err
+	}
+	return applyLines(r, idx, path, staged, work, lines, false)
+}
+
+// UnstageLines takes some of the changed lines of FileHunks.Staged back
+// out of the index, leaving the worktree alone
+func UnstageLines(r *git.Repository, path string, lines []DiffLine) error {
+	head, staged, _, idx, err := fileVersions(r, path)
+	if err != nil {
+		return err
+	}
+	return applyLines(r, idx, path, head, staged, lines, true)
+}
+
+// applyLines writes the index entry for path from the diff between from
+// and to. Staging moves the index (from) towards the worktree (to) by the
+// selected lines; reverse moves the index (to) back towards HEAD (from).
+// An entry that ends up matching a side where the file does not exist is
+// removed, which is how a deletion is staged or a new file unstaged.
+func applyLines(r *git.Repository, idx *index.Index, path string, from, to fileVersion, lines []DiffLine, reverse bool) error {
+	if isBinary(from.content) || isBinary(to.content) {
+		return fmt.Errorf("%s is binary, stage it as a whole", path)
+	}
+
+	selected := make(map[DiffLine]bool, len(lines))
+	for _, l := range lines {
+		if l.Kind != DiffContext {
+			selected[l] = true
+		}
+	}
+
+	var kept []DiffLine
+	found := 0
+	for _, l := range textDiffLines(from.content, to.content) {
+		sel := selected[l]
+		if sel {
+			found++
+		}
+		if l.Kind == DiffContext || (l.Kind == DiffDel && sel == reverse) || (l.Kind == DiffAdd && sel != reverse) {
+			kept = append(kept, l)
+		}
+	}
+	if len(selected) == 0 {
+		return fmt.Errorf("no changed lines selected in %s", path)
+	}
+	if found < len(selected) {
+		return fmt.Errorf("%s changed since its diff was shown, reload it", path)
+	}
+
+	var b strings.Builder
+	for i, l := range kept {
+		b.WriteString(l.Text)
+		// A missing newline only stays missing on the last line of the file
+		if !l.NoNewline || i < len(kept)-1 {
+			b.WriteString("\n")
+		}
+	}
+	content := []byte(b.String())
+
+	// The side the index is moving towards, and the one it leaves
+	target, current := to, from
+	if reverse {
+		target, current = from, to
+	}
+	complete := bytes.Equal(content, target.content)
+	if complete && !target.ok {
+		removeIndexEntry(idx, path)
+		return r.Storer.SetIndex(idx)
+	}
+
+	mode := current.mode
+	if complete || !current.ok {
+		mode = target.mode
+	}
+	hash, err := writeBlob(r, content)
+	if err != nil {
+		return err
+	}
+	setIndexEntry(idx, path, hash, mode, len(content))
+	return r.Storer.SetIndex(idx)
+}
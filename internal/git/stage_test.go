@@ -0,0 +1,238 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// statusRows renders the status as "path:code" rows, staged rows marked
+// with + and renames as "old>new:R90"
+func statusRows(t *testing.T, r *git.Repository) string {
+	t.Helper()
+	return statusRowsWith(t, r, RenameOptions{Threshold: DefaultRenameThreshold})
+}
+
+func statusRowsWith(t *testing.T, r *git.Repository, renames RenameOptions) string {
+	t.Helper()
+	ws, err := GetWorkingDirStatus(r, renames)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []string
+	for _, f := range ws.Files {
+		row := f.Path + ":" + f.Status
+		if f.OldPath != "" {
+			row = fmt.Sprintf("%s>%s:%s%d", f.OldPath, f.Path, f.Status, f.Similarity)
+		}
+		if f.Staged {
+			row = "+" + row
+		}
+		rows = append(rows, row)
+	}
+	return strings.Join(rows, " ")
+}
+
+func TestStaging(t *testing.T) {
+	r, dir := newTestRepo(t)
+	commitFile(t, r, dir, "dir/b.txt", "b\n", "b")
+	commitFile(t, r, dir, "dir/c.txt", "c\n", "c")
+	commitFile(t, r, dir, "a.txt", "1\n", "a")
+
+	writeFile(t, dir, "a.txt", "2\n")
+	if err := StagePaths(r, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "a.txt", "3\n")
+	writeFile(t, dir, "new.txt", "n\n")
+	writeFile(t, dir, "dir/c.txt", "changed\n")
+	if err := os.Remove(filepath.Join(dir, "dir/b.txt")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := statusRows(t, r), "+a.txt:M a.txt:M dir/b.txt:D dir/c.txt:M new.txt:?"; got != want {
+		t.Errorf("status = %q; want %q", got, want)
+	}
+
+	// A directory stages everything below it, deletions included
+	if err := StagePaths(r, "dir"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := statusRows(t, r), "+a.txt:M a.txt:M +dir/b.txt:D +dir/c.txt:M new.txt:?"; got != want {
+		t.Errorf("after staging dir = %q; want %q", got, want)
+	}
+
+	if err := UnstagePaths(r, "a.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := statusRows(t, r), "a.txt:M +dir/b.txt:D +dir/c.txt:M new.txt:?"; got != want {
+		t.Errorf("after unstaging a.txt = %q; want %q", got, want)
+	}
+	if got := readTestFile(t, dir, "a.txt"); got != "3\n" {
+		t.Errorf("unstaging touched the worktree: %q", got)
+	}
+
+	if err := StagePaths(r); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := statusRows(t, r), "+a.txt:M +dir/b.txt:D +dir/c.txt:M +new.txt:A"; got != want {
+		t.Errorf("after staging everything = %q; want %q", got, want)
+	}
+
+	// Unstaging everything turns the new file back into an untracked one
+	if err := UnstagePaths(r); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := statusRows(t, r), "a.txt:M dir/b.txt:D dir/c.txt:M new.txt:?"; got != want {
+		t.Errorf("after unstaging everything = %q; want %q", got, want)
+	}
+}
+
+// changedLines returns the added and removed lines of the given hunks
+func changedLines(f FileDiff, hunks ...int) []DiffLine {
+	var lines []DiffLine
+	for _, i := range hunks {
+		for _, l := range f.Hunks[i].Lines {
+			if l.Kind != DiffContext {
+				lines = append(lines, l)
+			}
+		}
+	}
+	return lines
+}
+
+// stagedContent reads the index version of a file
+func stagedContent(t *testing.T, r *git.Repository, path string) string {
+	t.Helper()
+	_, staged, _, _, err := fileVersions(r, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(staged.content)
+}
+
+func TestLineStaging(t *testing.T) {
+	r, dir := newTestRepo(t)
+	before := numbered(20)
+	commitFile(t, r, dir, "gone.txt", "x\ny\n", "gone")
+	commitFile(t, r, dir, "tail.txt", "a\nb", "tail")
+	commitFile(t, r, dir, "file.txt", strings.Join(before, ""), "file")
+
+	after := append([]string{}, before...)
+	after[1] = "changed 2\n"
+	after[17] = "changed 18\n"
+	writeFile(t, dir, "file.txt", strings.Join(after, ""))
+
+	hunks, err := GetFileHunks(r, "file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hunks.Unstaged.Hunks) != 2 || len(hunks.Staged.Hunks) != 0 {
+		t.Fatalf("hunks = %d unstaged, %d staged; want 2, 0", len(hunks.Unstaged.Hunks), len(hunks.Staged.Hunks))
+	}
+
+	// Staging the second hunk leaves the first one in the worktree only
+	if err := StageLines(r, "file.txt", changedLines(hunks.Unstaged, 1)); err != nil {
+		t.Fatal(err)
+	}
+	partial := append([]string{}, before...)
+	partial[17] = "changed 18\n"
+	if got := stagedContent(t, r, "file.txt"); got != strings.Join(partial, "") {
+		t.Errorf("staged file.txt = %q", got)
+	}
+	if got, want := statusRows(t, r), "+file.txt:M file.txt:M"; got != want {
+		t.Errorf("status = %q; want %q", got, want)
+	}
+
+	// The same lines now sit on the staged side and unstage back to HEAD
+	if hunks, err = GetFileHunks(r, "file.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if got := hunks.Staged.Hunks[0].Header(); got != "@@ -15,6 +15,6 @@" {
+		t.Errorf("staged hunk = %s", got)
+	}
+	if err := UnstageLines(r, "file.txt", changedLines(hunks.Staged, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := statusRows(t, r), "file.txt:M"; got != want {
+		t.Errorf("after unstaging = %q; want %q", got, want)
+	}
+
+	// Lines from a diff that no longer matches the file are refused
+	stale := []DiffLine{{Kind: DiffAdd, NewLine: 2, Text: "changed 2 again"}}
+	if err := StageLines(r, "file.txt", stale); err == nil {
+		t.Error("staging stale lines succeeded")
+	}
+
+	// One line of a new file adds it to the index with just that line
+	writeFile(t, dir, "new.txt", "one\ntwo\n")
+	hunks, err = GetFileHunks(r, "new.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := StageLines(r, "new.txt", hunks.Unstaged.Hunks[0].Lines[1:]); err != nil {
+		t.Fatal(err)
+	}
+	if got := stagedContent(t, r, "new.txt"); got != "two\n" {
+		t.Errorf("staged new.txt = %q", got)
+	}
+	hunks, err = GetFileHunks(r, "new.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := UnstageLines(r, "new.txt", changedLines(hunks.Staged, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := statusRows(t, r), "file.txt:M new.txt:?"; got != want {
+		t.Errorf("after unstaging new.txt = %q; want %q", got, want)
+	}
+
+	// Part of a deletion keeps the file, all of it stages the deletion
+	if err := os.Remove(filepath.Join(dir, "gone.txt")); err != nil {
+		t.Fatal(err)
+	}
+	hunks, err = GetFileHunks(r, "gone.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := changedLines(hunks.Unstaged, 0)
+	if err := StageLines(r, "gone.txt", lines[:1]); err != nil {
+		t.Fatal(err)
+	}
+	if got := stagedContent(t, r, "gone.txt"); got != "y\n" {
+		t.Errorf("staged gone.txt = %q", got)
+	}
+	hunks, err = GetFileHunks(r, "gone.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := StageLines(r, "gone.txt", changedLines(hunks.Unstaged, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := statusRows(t, r), "file.txt:M +gone.txt:D new.txt:?"; got != want {
+		t.Errorf("after staging the deletion = %q; want %q", got, want)
+	}
+
+	// Adding a line after one without a newline gives that line its newline
+	writeFile(t, dir, "tail.txt", "a\nb\nc\n")
+	hunks, err = GetFileHunks(r, "tail.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var added []DiffLine
+	for _, l := range changedLines(hunks.Unstaged, 0) {
+		if l.Kind == DiffAdd && l.Text == "c" {
+			added = append(added, l)
+		}
+	}
+	if err := StageLines(r, "tail.txt", added); err != nil {
+		t.Fatal(err)
+	}
+	if got := stagedContent(t, r, "tail.txt"); got != "a\nb\nc\n" {
+		t.Errorf("staged tail.txt = %q", got)
+	}
+}
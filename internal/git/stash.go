@@ -1,22 +1,590 @@
 package git
 
 import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
 )
 
+const stashRefName = plumbing.ReferenceName("refs/stash")
+
 type StashEntry struct {
 	ID        int
 	Message   string
+	Branch    string // Branch the stash was created on, "(no branch)" when detached
 	Timestamp time.Time
 	Hash      string
 }
 
-// GetStashList returns the list of stash entries.
-// Note: go-git support for stash is limited, we might need to parse refs/stash.
+// GetStashList returns the list of stash entries, stash@{0} first.
+// Entries come from the refs/stash reflog; when the reflog has been expired
+// the ref itself still yields the latest stash.
 func GetStashList(r *git.Repository) ([]StashEntry, error) {
-	// For now, returning empty to implement UI structure.
-	// Real implementation would iterate over refs/stash and its reflog.
-	return []StashEntry{}, nil
+	logEntries, err := stashReflog(r)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []StashEntry{}
+	for i, le := range logEntries {
+		if _, err := r.CommitObject(le.New); err != nil {
+			// Pruned object, keep the numbering git would use but skip the entry
+			continue
+		}
+
+		entries = append(entries, StashEntry{
+			ID:        i,
+			Message:   le.Message,
+			Branch:    stashBranch(le.Message),
+			Timestamp: le.Committer.When,
+			Hash:      le.New.String(),
+		})
+	}
+
+	return entries, nil
+}
+
+// stashReflog returns the refs/stash log newest first, so index n is stash@{n}
+func stashReflog(r *git.Repository) ([]ReflogEntry, error) {
+	ref, err := r.Reference(stashRefName, true)
+	if err == plumbing.ErrReferenceNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	logEntries, err := readReflog(r, stashRefName)
+	if err != nil {
+		return nil, err
+	}
+
+	// refs/stash was updated without logging (or the log was expired),
+	// so the ref is the only reliable source for stash@{0}
+	if len(logEntries) == 0 || logEntries[0].New != ref.Hash() {
+		top := ReflogEntry{New: ref.Hash()}
+		if len(logEntries) > 0 {
+			top.Old = logEntries[0].New
+		}
+		if commit, err := r.CommitObject(ref.Hash()); err == nil {
+			top.Message = commitSubject(commit.Message)
+			top.Committer = commit.Committer
+		}
+		logEntries = append([]ReflogEntry{top}, logEntries...)
+	}
+
+	return logEntries, nil
+}
+
+// stashByID returns the stash commit for stash@{id}
+func stashByID(r *git.Repository, id int, hash string) (*object.Commit, error) {
+	logEntries, err := stashReflog(r)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkStashEntry(logEntries, id, hash); err != nil {
+		return nil, err
+	}
+	return r.CommitObject(logEntries[id].New)
+}
+
+// checkStashEntry makes sure stash@{id} is still the stash with the given
+// hash. Entries are numbered by position, a stash made or dropped elsewhere
+// shifts them.
+func checkStashEntry(logEntries []ReflogEntry, id int, hash string) error {
+	if id < 0 || id >= len(logEntries) {
+		return fmt.Errorf("stash@{%d} does not exist", id)
+	}
+	if logEntries[id].New.String() != hash {
+		return fmt.Errorf("stash@{%d} changed since the list was loaded, refresh and try again", id)
+	}
+	return nil
+}
+
+// stashBranch extracts the branch from "WIP on <branch>: ..." or "On <branch>: ..."
+func stashBranch(message string) string {
+	rest, ok := strings.CutPrefix(message, "WIP on ")
+	if !ok {
+		rest, ok = strings.CutPrefix(message, "On ")
+	}
+	if !ok {
+		return ""
+	}
+	branch, _, found := strings.Cut(rest, ":")
+	if !found {
+		return ""
+	}
+	return branch
+}
+
+// StashDetail describes what a stash entry records.
+// A stash commit has the base commit as first parent, the index commit as
+// second parent and, when created with --include-untracked, a third parent
+// holding the untracked files.
+type StashDetail struct {
+	Hash      string
+	Base      string
+	Staged    []FileChange // Index commit against the base
+	Unstaged  []FileChange // Worktree state against the index commit
+	Untracked []FileChange
+	Patch     *object.Patch // Tracked changes against the base commit
+}
+
+// GetStashDetail inspects the stash commit with the given hash
+func GetStashDetail(r *git.Repository, hash string) (*StashDetail, error) {
+	stash, err := r.CommitObject(plumbing.NewHash(hash))
+	if err != nil {
+		return nil, err
+	}
+	if stash.NumParents() < 2 {
+		return nil, fmt.Errorf("%s is not a stash commit", stash.Hash.String()[:7])
+	}
+
+	stashTree, err := stash.Tree()
+	if err != nil {
+		return nil, err
+	}
+	baseTree, err := commitTree(r, stash.ParentHashes[0])
+	if err != nil {
+		return nil, err
+	}
+	indexTree, err := commitTree(r, stash.ParentHashes[1])
+	if err != nil {
+		return nil, err
+	}
+
+	d := &StashDetail{
+		Hash: stash.Hash.String(),
+		Base: stash.ParentHashes[0].String(),
+	}
+
+	if d.Staged, err = treeChanges(baseTree, indexTree); err != nil {
+		return nil, err
+	}
+	if d.Unstaged, err = treeChanges(indexTree, stashTree); err != nil {
+		return nil, err
+	}
+
+	d.Untracked = []FileChange{}
+	if stash.NumParents() > 2 {
+		untrackedTree, err := commitTree(r, stash.ParentHashes[2])
+		if err != nil {
+			return nil, err
+		}
+		if d.Untracked, err = treeChanges(&object.Tree{}, untrackedTree); err != nil {
+			return nil, err
+		}
+	}
+
+	if d.Patch, err = baseTree.Patch(stashTree); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// StashApplyResult reports what applying a stash did to the worktree
+type StashApplyResult struct {
+	Updated   []string // Paths written or removed in the worktree
+	Conflicts []string // Paths that could not be merged; nothing is written when set
+}
+
+// stashWrite is a planned worktree change made while applying a stash
+type stashWrite struct {
+	path    string
+	content []byte
+	mode    filemode.FileMode
+	remove  bool
+	stage   bool // New or deleted paths are recorded in the index, like git does
+}
+
+// CreateStash saves local changes as stash@{0} and resets the tracked files
+// to HEAD, like `git stash push`. Untracked files are stashed and removed
+// only when includeUntracked is set.
+func CreateStash(r *git.Repository, message string, includeUntracked bool) (*StashEntry, error) {
+	head, err := r.Head()
+	if err != nil {
+		return nil, fmt.Errorf("cannot stash without an initial commit: %w", err)
+	}
+	headCommit, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range idx.Entries {
+		if e.Stage != stageMerged {
+			return nil, fmt.Errorf("cannot stash with unmerged path %s", e.Name)
+		}
+	}
+
+	var tracked, untracked []string
+	for p, s := range status {
+		if s.Worktree == git.Untracked && s.Staging == git.Untracked {
+			untracked = append(untracked, p)
+			continue
+		}
+		if s.Worktree != git.Unmodified || s.Staging != git.Unmodified {
+			tracked = append(tracked, p)
+		}
+	}
+	if !includeUntracked {
+		untracked = nil
+	}
+	if len(tracked) == 0 && len(untracked) == 0 {
+		return nil, errors.New("no local changes to save")
+	}
+
+	branch := "(no branch)"
+	if head.Name().IsBranch() {
+		branch = head.Name().Short()
+	}
+	summary := fmt.Sprintf("%s: %s %s", branch, head.Hash().String()[:7], commitSubject(headCommit.Message))
+	sig := currentSignature(r)
+
+	// Index commit
+	indexState := indexFiles(idx)
+	indexTree, err := writeTree(r, indexState)
+	if err != nil {
+		return nil, err
+	}
+	indexCommit, err := writeCommit(r, indexTree, []plumbing.Hash{head.Hash()}, sig, "index on "+summary+"\n")
+	if err != nil {
+		return nil, err
+	}
+
+	// Worktree tree: the index with every tracked worktree change applied
+	worktreeState := indexFiles(idx)
+	for _, p := range tracked {
+		if _, inIndex := worktreeState[p]; !inIndex || status[p].Worktree == git.Untracked {
+			continue
+		}
+		content, mode, ok, err := readWorktreeFile(w, p)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			delete(worktreeState, p)
+			continue
+		}
+		hash, err := writeBlob(r, content)
+		if err != nil {
+			return nil, err
+		}
+		worktreeState[p] = treeFile{Hash: hash, Mode: mode}
+	}
+	worktreeTree, err := writeTree(r, worktreeState)
+	if err != nil {
+		return nil, err
+	}
+
+	parents := []plumbing.Hash{head.Hash(), indexCommit}
+
+	// Untracked files go into a parentless third commit
+	if len(untracked) > 0 {
+		untrackedState := make(map[string]treeFile)
+		for _, p := range untracked {
+			content, mode, ok, err := readWorktreeFile(w, p)
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				continue
+			}
+			hash, err := writeBlob(r, content)
+			if err != nil {
+				return nil, err
+			}
+			untrackedState[p] = treeFile{Hash: hash, Mode: mode}
+		}
+		untrackedTree, err := writeTree(r, untrackedState)
+		if err != nil {
+			return nil, err
+		}
+		untrackedCommit, err := writeCommit(r, untrackedTree, nil, sig, "untracked files on "+summary+"\n")
+		if err != nil {
+			return nil, err
+		}
+		parents = append(parents, untrackedCommit)
+	}
+
+	stashMsg := "WIP on " + summary
+	if message != "" {
+		stashMsg = fmt.Sprintf("On %s: %s", branch, message)
+	}
+	stashCommit, err := writeCommit(r, worktreeTree, parents, sig, stashMsg+"\n")
+	if err != nil {
+		return nil, err
+	}
+
+	// Record the stash before touching any file
+	old := plumbing.ZeroHash
+	if ref, err := r.Reference(stashRefName, true); err == nil {
+		old = ref.Hash()
+	}
+	if err := r.Storer.SetReference(plumbing.NewHashReference(stashRefName, stashCommit)); err != nil {
+		return nil, err
+	}
+	if err := appendReflog(r, stashRefName, ReflogEntry{Old: old, New: stashCommit, Committer: sig, Message: stashMsg}); err != nil {
+		return nil, err
+	}
+
+	// Reset tracked paths to HEAD without go-git's hard reset, which would
+	// also wipe untracked and ignored files
+	headState, err := treeFiles(headTree)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range tracked {
+		hf, inHead := headState[p]
+		if !inHead {
+			removeIndexEntry(idx, p)
+			if err := removeWorktreeFile(w, p); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		content, err := blobContent(r, hf.Hash)
+		if err != nil {
+			return nil, err
+		}
+		if err := writeWorktreeFile(w, p, content, hf.Mode); err != nil {
+			return nil, err
+		}
+		setIndexEntry(idx, p, hf.Hash, hf.Mode, len(content))
+	}
+	if err := r.Storer.SetIndex(idx); err != nil {
+		return nil, err
+	}
+	for _, p := range untracked {
+		if err := removeWorktreeFile(w, p); err != nil {
+			return nil, err
+		}
+	}
+
+	return &StashEntry{
+		ID:        0,
+		Message:   stashMsg,
+		Branch:    branch,
+		Timestamp: sig.When,
+		Hash:      stashCommit.String(),
+	}, nil
+}
+
+// ApplyStash merges stash@{id}, which must still be the stash with the
+// given hash, into the worktree. Each path is merged against the stash
+// base; if any path conflicts, nothing is written and the conflicting paths
+// are returned so the stash can be applied by hand.
+func ApplyStash(r *git.Repository, id int, hash string) (*StashApplyResult, error) {
+	stash, err := stashByID(r, id, hash)
+	if err != nil {
+		return nil, err
+	}
+	if stash.NumParents() < 2 {
+		return nil, fmt.Errorf("stash@{%d} is not a stash commit", id)
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	baseTree, err := commitTree(r, stash.ParentHashes[0])
+	if err != nil {
+		return nil, err
+	}
+	stashTree, err := stash.Tree()
+	if err != nil {
+		return nil, err
+	}
+	baseState, err := treeFiles(baseTree)
+	if err != nil {
+		return nil, err
+	}
+	stashState, err := treeFiles(stashTree)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := treeChanges(baseTree, stashTree)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &StashApplyResult{Updated: []string{}, Conflicts: []string{}}
+	var writes []stashWrite
+
+	for _, ch := range changes {
+		p := ch.Path
+		base, inBase := baseState[p]
+		theirs, inTheirs := stashState[p]
+		ours, oursMode, inOurs, err := readWorktreeFile(w, p)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case inOurs && inTheirs && blobHash(ours) == theirs.Hash:
+			continue // Already there
+		case !inOurs && !inTheirs:
+			continue // Already deleted
+		case inOurs == inBase && (!inBase || blobHash(ours) == base.Hash):
+			// Untouched locally, take the stashed version
+			if !inTheirs {
+				writes = append(writes, stashWrite{path: p, remove: true, stage: true})
+				continue
+			}
+			content, err := blobContent(r, theirs.Hash)
+			if err != nil {
+				return nil, err
+			}
+			writes = append(writes, stashWrite{path: p, content: content, mode: theirs.Mode, stage: !inBase})
+		case inBase && inOurs && inTheirs:
+			baseContent, err := blobContent(r, base.Hash)
+			if err != nil {
+				return nil, err
+			}
+			theirsContent, err := blobContent(r, theirs.Hash)
+			if err != nil {
+				return nil, err
+			}
+			if isBinary(baseContent) || isBinary(ours) || isBinary(theirsContent) {
+				result.Conflicts = append(result.Conflicts, p)
+				continue
+			}
+			merged, ok := mergeText(string(baseContent), string(ours), string(theirsContent))
+			if !ok {
+				result.Conflicts = append(result.Conflicts, p)
+				continue
+			}
+			writes = append(writes, stashWrite{path: p, content: []byte(merged), mode: oursMode})
+		default:
+			// Added on both sides differently, or modified on one side and deleted on the other
+			result.Conflicts = append(result.Conflicts, p)
+		}
+	}
+
+	// Untracked files come back as untracked, refusing to overwrite anything
+	if stash.NumParents() > 2 {
+		untrackedTree, err := commitTree(r, stash.ParentHashes[2])
+		if err != nil {
+			return nil, err
+		}
+		untrackedState, err := treeFiles(untrackedTree)
+		if err != nil {
+			return nil, err
+		}
+		for p, f := range untrackedState {
+			ours, _, inOurs, err := readWorktreeFile(w, p)
+			if err != nil {
+				return nil, err
+			}
+			if inOurs {
+				if blobHash(ours) != f.Hash {
+					result.Conflicts = append(result.Conflicts, p)
+				}
+				continue
+			}
+			content, err := blobContent(r, f.Hash)
+			if err != nil {
+				return nil, err
+			}
+			writes = append(writes, stashWrite{path: p, content: content, mode: f.Mode})
+		}
+	}
+
+	if len(result.Conflicts) > 0 {
+		sort.Strings(result.Conflicts)
+		return result, nil
+	}
+
+	for _, wr := range writes {
+		if wr.remove {
+			if err := removeWorktreeFile(w, wr.path); err != nil {
+				return nil, err
+			}
+			if wr.stage {
+				removeIndexEntry(idx, wr.path)
+			}
+		} else {
+			if err := writeWorktreeFile(w, wr.path, wr.content, wr.mode); err != nil {
+				return nil, err
+			}
+			if wr.stage {
+				setIndexEntry(idx, wr.path, blobHash(wr.content), wr.mode, len(wr.content))
+			}
+		}
+		result.Updated = append(result.Updated, wr.path)
+	}
+	if err := r.Storer.SetIndex(idx); err != nil {
+		return nil, err
+	}
+
+	sort.Strings(result.Updated)
+	return result, nil
+}
+
+// PopStash applies stash@{id} and drops it when it applied cleanly.
+// On conflicts the entry is kept.
+func PopStash(r *git.Repository, id int, hash string) (*StashApplyResult, error) {
+	result, err := ApplyStash(r, id, hash)
+	if err != nil || len(result.Conflicts) > 0 {
+		return result, err
+	}
+	return result, DropStash(r, id, hash)
+}
+
+// DropStash removes stash@{id}, renumbering the entries above it. Like
+// ApplyStash it refuses when the entry is no longer the one with hash.
+func DropStash(r *git.Repository, id int, hash string) error {
+	logEntries, err := stashReflog(r)
+	if err != nil {
+		return err
+	}
+	if err := checkStashEntry(logEntries, id, hash); err != nil {
+		return err
+	}
+
+	remaining := append(logEntries[:id:id], logEntries[id+1:]...)
+	if len(remaining) == 0 {
+		if err := r.Storer.RemoveReference(stashRefName); err != nil {
+			return err
+		}
+		return writeReflog(r, stashRefName, nil)
+	}
+
+	// Keep the old -> new chain intact across the gap, as git does
+	if id > 0 {
+		remaining[id-1].Old = logEntries[id].Old
+	}
+
+	if err := r.Storer.SetReference(plumbing.NewHashReference(stashRefName, remaining[0].New)); err != nil {
+		return err
+	}
+	return writeReflog(r, stashRefName, remaining)
 }
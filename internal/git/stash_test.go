@@ -0,0 +1,292 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// storeStashCommit writes a stash-shaped commit on top of HEAD without touching refs
+func storeStashCommit(t *testing.T, r *git.Repository, msg string) plumbing.Hash {
+	t.Helper()
+	head, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := r.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := &object.Commit{
+		Author:       testSignature,
+		Committer:    testSignature,
+		Message:      msg,
+		TreeHash:     parent.TreeHash,
+		ParentHashes: []plumbing.Hash{parent.Hash},
+	}
+	obj := r.Storer.NewEncodedObject()
+	if err := c.Encode(obj); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := r.Storer.SetEncodedObject(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func writeStashReflog(t *testing.T, dir string, lines ...string) {
+	t.Helper()
+	writeFile(t, dir, filepath.Join(".git", "logs", "refs", "stash"), strings.Join(lines, "\n")+"\n")
+}
+
+func reflogLine(old, new plumbing.Hash, ts int64, msg string) string {
+	return fmt.Sprintf("%s %s Test <test@example.com> %d +0000\t%s", old, new, ts, msg)
+}
+
+func TestGetStashListEmpty(t *testing.T) {
+	r, dir := newTestRepo(t)
+	commitFile(t, r, dir, "a.txt", "a\n", "initial")
+
+	entries, err := GetStashList(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("GetStashList() = %d entries; want 0", len(entries))
+	}
+}
+
+func TestGetStashListFromReflog(t *testing.T) {
+	r, dir := newTestRepo(t)
+	commitFile(t, r, dir, "a.txt", "a\n", "initial")
+
+	first := storeStashCommit(t, r, "WIP on master: first")
+	second := storeStashCommit(t, r, "On feature/x: second")
+	if err := r.Storer.SetReference(plumbing.NewHashReference(stashRefName, second)); err != nil {
+		t.Fatal(err)
+	}
+	writeStashReflog(t, dir,
+		reflogLine(plumbing.ZeroHash, first, 1700000000, "WIP on master: 1234567 first"),
+		reflogLine(first, second, 1700000100, "On feature/x: second"),
+	)
+
+	entries, err := GetStashList(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("GetStashList() = %d entries; want 2", len(entries))
+	}
+
+	if entries[0].ID != 0 || entries[0].Hash != second.String() || entries[0].Branch != "feature/x" {
+		t.Errorf("entries[0] = %+v; want stash@{0} of %s on feature/x", entries[0], second)
+	}
+	if entries[1].ID != 1 || entries[1].Hash != first.String() || entries[1].Branch != "master" {
+		t.Errorf("entries[1] = %+v; want stash@{1} of %s on master", entries[1], first)
+	}
+	if entries[1].Message != "WIP on master: 1234567 first" {
+		t.Errorf("entries[1].Message = %q", entries[1].Message)
+	}
+	if entries[0].Timestamp.Unix() != 1700000100 {
+		t.Errorf("entries[0].Timestamp = %v; want reflog time", entries[0].Timestamp)
+	}
+}
+
+func TestGetStashListExpiredReflog(t *testing.T) {
+	r, dir := newTestRepo(t)
+	commitFile(t, r, dir, "a.txt", "a\n", "initial")
+
+	only := storeStashCommit(t, r, "WIP on master: abcdef0 only\n")
+	if err := r.Storer.SetReference(plumbing.NewHashReference(stashRefName, only)); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := GetStashList(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("GetStashList() = %d entries; want 1", len(entries))
+	}
+	if entries[0].Message != "WIP on master: abcdef0 only" || entries[0].Branch != "master" {
+		t.Errorf("entries[0] = %+v; want message taken from the stash commit", entries[0])
+	}
+	if !entries[0].Timestamp.Equal(testSignature.When) {
+		t.Errorf("entries[0].Timestamp = %v; want commit time", entries[0].Timestamp)
+	}
+}
+
+func TestGetStashListPrunedEntry(t *testing.T) {
+	r, dir := newTestRepo(t)
+	commitFile(t, r, dir, "a.txt", "a\n", "initial")
+
+	missing := plumbing.NewHash("0123456789012345678901234567890123456789")
+	top := storeStashCommit(t, r, "On master: top")
+	if err := r.Storer.SetReference(plumbing.NewHashReference(stashRefName, top)); err != nil {
+		t.Fatal(err)
+	}
+	writeStashReflog(t, dir,
+		reflogLine(plumbing.ZeroHash, missing, 1700000000, "On master: gone"),
+		"not a reflog line",
+		reflogLine(missing, top, 1700000100, "On master: top"),
+	)
+
+	entries, err := GetStashList(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ID != 0 || entries[0].Hash != top.String() {
+		t.Errorf("GetStashList() = %+v; want only stash@{0}", entries)
+	}
+}
+
+func readTestFile(t *testing.T, dir, name string) string {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestCreateAndPopStash(t *testing.T) {
+	r, dir := newTestRepo(t)
+	commitFile(t, r, dir, "a.txt", "one\ntwo\nthree\nfour\n", "initial")
+
+	writeFile(t, dir, "a.txt", "ONE\ntwo\nthree\nfour\n")
+	writeFile(t, dir, "staged.txt", "staged\n")
+	w, _ := r.Worktree()
+	if _, err := w.Add("staged.txt"); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "untracked.txt", "untracked\n")
+
+	entry, err := CreateStash(r, "", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Branch != "master" || !strings.HasPrefix(entry.Message, "WIP on master: ") {
+		t.Errorf("CreateStash() = %+v", entry)
+	}
+
+	status, _ := w.Status()
+	if !status.IsClean() {
+		t.Errorf("worktree not clean after stash:\n%s", status)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "untracked.txt")); !os.IsNotExist(err) {
+		t.Error("untracked.txt should have been stashed away")
+	}
+
+	detail, err := GetStashDetail(r, entry.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(detail.Staged) != 1 || len(detail.Unstaged) != 1 || len(detail.Untracked) != 1 {
+		t.Errorf("GetStashDetail() = staged %v, unstaged %v, untracked %v", detail.Staged, detail.Unstaged, detail.Untracked)
+	}
+
+	// A non overlapping local edit merges with the stashed one
+	writeFile(t, dir, "a.txt", "one\ntwo\nthree\nFOUR\n")
+
+	result, err := PopStash(r, 0, entry.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conflicts) != 0 {
+		t.Fatalf("PopStash() conflicts = %v", result.Conflicts)
+	}
+	if got := readTestFile(t, dir, "a.txt"); got != "ONE\ntwo\nthree\nFOUR\n" {
+		t.Errorf("a.txt = %q after pop", got)
+	}
+	if got := readTestFile(t, dir, "untracked.txt"); got != "untracked\n" {
+		t.Errorf("untracked.txt = %q after pop", got)
+	}
+
+	entries, _ := GetStashList(r)
+	if len(entries) != 0 {
+		t.Errorf("stash list = %v; want empty after pop", entries)
+	}
+}
+
+func TestApplyStashConflictKeepsEntry(t *testing.T) {
+	r, dir := newTestRepo(t)
+	commitFile(t, r, dir, "a.txt", "one\ntwo\n", "initial")
+
+	writeFile(t, dir, "a.txt", "stashed\ntwo\n")
+	entry, err := CreateStash(r, "mine", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, dir, "a.txt", "local\ntwo\n")
+
+	result, err := PopStash(r, 0, entry.Hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Conflicts) != 1 || result.Conflicts[0] != "a.txt" {
+		t.Errorf("PopStash() conflicts = %v; want [a.txt]", result.Conflicts)
+	}
+	if got := readTestFile(t, dir, "a.txt"); got != "local\ntwo\n" {
+		t.Errorf("a.txt = %q; conflicting apply must not write", got)
+	}
+
+	entries, _ := GetStashList(r)
+	if len(entries) != 1 || entries[0].Message != "On master: mine" {
+		t.Errorf("stash list = %+v; want the entry kept", entries)
+	}
+}
+
+func TestDropStash(t *testing.T) {
+	r, dir := newTestRepo(t)
+	commitFile(t, r, dir, "a.txt", "a\n", "initial")
+
+	for _, msg := range []string{"first", "second", "third"} {
+		writeFile(t, dir, "a.txt", msg+"\n")
+		if _, err := CreateStash(r, msg, false); err != nil {
+			t.Fatal(err)
+		}
+	}
+	entries, _ := GetStashList(r)
+
+	// Positions are checked against the hash the list showed
+	if err := DropStash(r, 1, entries[0].Hash); err == nil {
+		t.Error("DropStash(1) with the hash of stash@{0} should fail")
+	}
+	if _, err := ApplyStash(r, 0, entries[1].Hash); err == nil {
+		t.Error("ApplyStash(0) with the hash of stash@{1} should fail")
+	}
+
+	if err := DropStash(r, 1, entries[1].Hash); err != nil {
+		t.Fatal(err)
+	}
+	dropped := entries
+	entries, _ = GetStashList(r)
+	if len(entries) != 2 || entries[0].Message != "On master: third" || entries[1].Message != "On master: first" {
+		t.Fatalf("after dropping stash@{1}: %+v", entries)
+	}
+	if entries[1].ID != 1 {
+		t.Errorf("entries renumbered to %d; want 1", entries[1].ID)
+	}
+
+	// stash@{1} is now "first", the stale entry must not drop it
+	if err := DropStash(r, 1, dropped[1].Hash); err == nil {
+		t.Error("DropStash(1) with a stale hash should fail")
+	}
+	if err := DropStash(r, 5, dropped[0].Hash); err == nil {
+		t.Error("DropStash(5) should fail")
+	}
+
+	DropStash(r, 0, entries[0].Hash)
+	DropStash(r, 0, entries[1].Hash)
+	if _, err := r.Reference(stashRefName, true); err != plumbing.ErrReferenceNotFound {
+		t.Errorf("refs/stash should be gone after dropping everything, got %v", err)
+	}
+}
@@ -1,14 +1,23 @@
 package git
 
 import (
+	"sort"
+
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 )
 
 type FileStatus struct {
 	Path   string
-	Status string // M, A, D, ?, etc.
-	Staged bool
+	Status string // M, A, D, R, C, ?, etc.
+	Staged bool   // Change between HEAD and the index, otherwise between the index and the worktree
+
+	// OldPath is where a renamed (R) or copied (C) file came from
+	OldPath    string
+	Similarity int // Percent of the content kept from OldPath
+
+	// Conflict is set for an unmerged path (Status U), e.g. "deleted by us"
+	Conflict string
 }
 
 type WorkingDirStatus struct {
@@ -18,10 +27,14 @@ type WorkingDirStatus struct {
 	Staged     int
 	Untracked  int
 	Conflicted int
+	Renamed    int // Renames and copies are not counted in Staged and Modified
+	Copied     int
 }
 
-// GetWorkingDirStatus returns the status of files in the working directory
-func GetWorkingDirStatus(r *git.Repository) (*WorkingDirStatus, error) {
+// GetWorkingDirStatus returns the status of files in the working directory.
+// Moved files are paired up into renames on each side, staged and unstaged,
+// rather than shown as a deletion and a new file.
+func GetWorkingDirStatus(r *git.Repository, renames RenameOptions) (*WorkingDirStatus, error) {
 	w, err := r.Worktree()
 	if err != nil {
 		return nil, err
@@ -50,59 +63,188 @@ func GetWorkingDirStatus(r *git.Repository) (*WorkingDirStatus, error) {
 		BranchName: branchName,
 	}
 
+	// go-git does not report unmerged paths, they come from the index stages
+	conflicts, err := GetConflicts(r)
+	if err != nil {
+		return nil, err
+	}
+	conflicted := make(map[string]bool, len(conflicts))
+	for _, c := range conflicts {
+		ws.Files = append(ws.Files, FileStatus{Path: c.Path, Status: "U", Conflict: c.Type()})
+		conflicted[c.Path] = true
+	}
+
+	// A file with both staged and unstaged changes gets a row for each
 	for path, s := range status {
-		code := string(s.Worktree)
-		stagedCode := string(s.Staging)
-
-		isStaged := stagedCode != " " && stagedCode != "?"
-		isModified := code == "M" || stagedCode == "M"
-		isUntracked := code == "?"
-		isDeleted := code == "D" || stagedCode == "D"
-		isAdded := stagedCode == "A"
-		isConflicted := code == "C" || stagedCode == "C" || code == "U" || stagedCode == "U"
-
-		// Determine display status
-		displayStatus := code
-		if isStaged {
-			displayStatus = stagedCode
-			ws.Staged++
+		switch {
+		case conflicted[path]:
+			continue
+		case s.Staging == git.Untracked && s.Worktree == git.Untracked:
+			ws.Files = append(ws.Files, FileStatus{Path: path, Status: "?"})
+			continue
 		}
 
-		if isModified && !isStaged {
-			ws.Modified++
+		if s.Staging != git.Unmodified {
+			ws.Files = append(ws.Files, FileStatus{Path: path, Status: string(s.Staging), Staged: true})
 		}
-
-		if isUntracked {
-			displayStatus = "?"
-			ws.Untracked++
+		if s.Worktree != git.Unmodified {
+			ws.Files = append(ws.Files, FileStatus{Path: path, Status: string(s.Worktree)})
 		}
+	}
 
-		if isConflicted {
+	if renames.Threshold > 0 {
+		if ws.Files, err = pairRenames(r, w, ws.Files, renames); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range ws.Files {
+		switch {
+		case f.Status == "U":
 			ws.Conflicted++
+		case f.Status == "?":
+			ws.Untracked++
+		case f.Status == "R":
+			ws.Renamed++
+		case f.Status == "C":
+			ws.Copied++
+		case f.Staged:
+			ws.Staged++
+		default:
+			ws.Modified++
 		}
+	}
 
-		// Consider Deleted and Added in Modified/Staged counts?
-		// For simplicity, let's just make sure they are counted.
-		if isDeleted && !isStaged {
-			ws.Modified++
+	sort.Slice(ws.Files, func(i, j int) bool {
+		if ws.Files[i].Path != ws.Files[j].Path {
+			return ws.Files[i].Path < ws.Files[j].Path
 		}
+		return ws.Files[i].Staged
+	})
+
+	return ws, nil
+}
 
-		if isAdded {
-			// ws.Staged++ // Already handled by isStaged logic
+// renameCandidates tells whether one side has both a possible source and
+// a new file, before any content is read
+func renameCandidates(files []FileStatus, staged bool, opts RenameOptions) bool {
+	source, added := false, false
+	for _, f := range files {
+		switch {
+		case staged && f.Staged && f.Status == "A", !staged && f.Status == "?":
+			added = true
+		case f.Staged == staged && (f.Status == "D" || f.Status == "M" && opts.Copies):
+			source = true
 		}
+	}
+	return source && added
+}
+
+// pairRenames replaces the deletion and the new file of each rename with a
+// single R row, and marks copies with C. Staged renames compare HEAD with
+// the index, unstaged ones the index with untracked worktree files.
+func pairRenames(r *git.Repository, w *git.Worktree, files []FileStatus, opts RenameOptions) ([]FileStatus, error) {
+	if !renameCandidates(files, true, opts) && !renameCandidates(files, false, opts) {
+		return files, nil
+	}
+	idx, err := r.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	headHash := plumbing.ZeroHash
+	if head, err := r.Head(); err == nil {
+		headHash = head.Hash()
+	}
+	headTree, err := commitTree(r, headHash)
+	if err != nil {
+		return nil, err
+	}
+	headFiles, err := treeFiles(headTree)
+	if err != nil {
+		return nil, err
+	}
+	indexed := indexFiles(idx)
 
-		ws.Files = append(ws.Files, FileStatus{
-			Path:   path,
-			Status: displayStatus,
-			Staged: isStaged,
-		})
+	blob := func(files map[string]treeFile, path string) (renameFile, error) {
+		f := renameFile{path: path, hash: files[path].Hash}
+		var err error
+		f.content, err = blobContent(r, f.hash)
+		return f, err
+	}
+	worktreeFile := func(path string) (renameFile, error) {
+		content, _, _, err := readWorktreeFile(w, path)
+		return renameFile{path: path, hash: plumbing.ComputeHash(plumbing.BlobObject, content), content: content}, err
 	}
 
-	// Sort files by path for consistent display?
-	// Or maybe group by status (Staged, Modified, Untracked)
-	// For MVP, just return list. UI can sort if needed.
+	for _, staged := range []bool{true, false} {
+		if !renameCandidates(files, staged, opts) {
+			continue
+		}
+		var deleted, modified, added []renameFile
+		for _, f := range files {
+			var rf renameFile
+			var err error
+			switch {
+			case f.Staged != staged && f.Status != "?":
+				continue
+			case f.Status == "M" && !opts.Copies:
+				continue
+			case staged && (f.Status == "D" || f.Status == "M"):
+				rf, err = blob(headFiles, f.Path)
+			case staged && f.Status == "A":
+				rf, err = blob(indexed, f.Path)
+			case !staged && (f.Status == "D" || f.Status == "M"):
+				rf, err = blob(indexed, f.Path)
+			case !staged && f.Status == "?":
+				rf, err = worktreeFile(f.Path)
+			default:
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			// Like git, empty files are never paired up
+			if len(rf.content) == 0 {
+				continue
+			}
+			switch f.Status {
+			case "D":
+				deleted = append(deleted, rf)
+			case "M":
+				modified = append(modified, rf)
+			default:
+				added = append(added, rf)
+			}
+		}
 
-	return ws, nil
+		matches := detectRenames(deleted, modified, added, opts)
+		if len(matches) == 0 {
+			continue
+		}
+		byPath := make(map[string]renameMatch, len(matches))
+		gone := make(map[string]bool)
+		for _, m := range matches {
+			byPath[m.to] = m
+			if !m.copy {
+				gone[m.from] = true
+			}
+		}
+		kept := files[:0:0]
+		for _, f := range files {
+			isSide := f.Staged == staged || (!staged && f.Status == "?")
+			if isSide && f.Status == "D" && gone[f.Path] {
+				continue
+			}
+			if m, ok := byPath[f.Path]; ok && isSide && f.Status != "D" && f.Status != "M" {
+				f.Status, f.OldPath, f.Similarity, f.Staged = "R", m.from, m.similarity, staged
+				if m.copy {
+					f.Status = "C"
+				}
+			}
+			kept = append(kept, f)
+		}
+		files = kept
+	}
+	return files, nil
 }
 
 // Helper to check if file is ignored?
@@ -0,0 +1,192 @@
+package git
+
+import (
+	"cmp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+type Tag struct {
+	Name        string
+	Hash        string // Commit the tag points at (peeled for annotated tags)
+	Annotated   bool
+	Tagger      string // Annotated tags only
+	TaggerEmail string
+	When        time.Time // Tagger date, or the commit date for lightweight tags
+	Message     string    // Annotated tags only
+}
+
+// TagSort selects the order returned by GetTags
+type TagSort int
+
+const (
+	TagSortSemver TagSort = iota // Highest version first, non-version tags last by name
+	TagSortDate                  // Newest first
+)
+
+// ParseTagSort maps a config value ("semver" or "date") to a TagSort
+func ParseTagSort(s string) TagSort {
+	if strings.EqualFold(s, "date") {
+		return TagSortDate
+	}
+	return TagSortSemver
+}
+
+func (s TagSort) String() string {
+	if s == TagSortDate {
+		return "date"
+	}
+	return "semver"
+}
+
+// GetTags returns lightweight and annotated tags. Tags that do not point at a
+// commit (e.g. a tagged tree) are skipped.
+func GetTags(r *git.Repository, order TagSort) ([]Tag, error) {
+	tags := []Tag{}
+
+	iter, err := r.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		t := Tag{Name: ref.Name().Short()}
+
+		target := ref.Hash()
+		if obj, err := r.TagObject(ref.Hash()); err == nil {
+			c, err := obj.Commit()
+			if err != nil {
+				return nil
+			}
+			target = c.Hash
+			t.Annotated = true
+			t.Tagger = obj.Tagger.Name
+			t.TaggerEmail = obj.Tagger.Email
+			t.When = obj.Tagger.When
+			t.Message = strings.TrimSpace(obj.Message)
+		}
+
+		c, err := r.CommitObject(target)
+		if err != nil {
+			return nil
+		}
+		t.Hash = c.Hash.String()
+		if !t.Annotated {
+			t.When = c.Committer.When
+		}
+
+		tags = append(tags, t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	SortTags(tags, order)
+	return tags, nil
+}
+
+// SortTags orders tags in place
+func SortTags(tags []Tag, order TagSort) {
+	sort.SliceStable(tags, func(i, j int) bool {
+		if order == TagSortDate {
+			if !tags[i].When.Equal(tags[j].When) {
+				return tags[i].When.After(tags[j].When)
+			}
+			return tags[i].Name < tags[j].Name
+		}
+
+		vi, oki := parseSemver(tags[i].Name)
+		vj, okj := parseSemver(tags[j].Name)
+		switch {
+		case oki && okj:
+			if c := compareSemver(vi, vj); c != 0 {
+				return c > 0
+			}
+			return tags[i].Name < tags[j].Name
+		case oki != okj:
+			return oki
+		default:
+			return tags[i].Name < tags[j].Name
+		}
+	})
+}
+
+type semver struct {
+	core [3]int
+	pre  []string
+}
+
+// parseSemver accepts MAJOR[.MINOR[.PATCH]][-PRERELEASE][+BUILD] with an
+// optional leading "v", which covers the usual release tag styles
+func parseSemver(name string) (semver, bool) {
+	var v semver
+
+	s := strings.TrimPrefix(strings.TrimPrefix(name, "v"), "V")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, hasPre := strings.Cut(s, "-")
+	if hasPre {
+		if pre == "" {
+			return v, false
+		}
+		v.pre = strings.Split(pre, ".")
+	}
+
+	parts := strings.Split(s, ".")
+	if len(parts) > 3 {
+		return v, false
+	}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return v, false
+		}
+		v.core[i] = n
+	}
+	return v, true
+}
+
+// compareSemver follows the semver precedence rules: a release outranks its
+// prereleases, and prerelease identifiers compare numerically when they can
+func compareSemver(a, b semver) int {
+	for i := range a.core {
+		if a.core[i] != b.core[i] {
+			return cmp.Compare(a.core[i], b.core[i])
+		}
+	}
+
+	switch {
+	case len(a.pre) == 0 && len(b.pre) == 0:
+		return 0
+	case len(a.pre) == 0:
+		return 1
+	case len(b.pre) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a.pre) && i < len(b.pre); i++ {
+		x, y := a.pre[i], b.pre[i]
+		nx, errx := strconv.Atoi(x)
+		ny, erry := strconv.Atoi(y)
+		switch {
+		case errx == nil && erry == nil:
+			if nx != ny {
+				return cmp.Compare(nx, ny)
+			}
+		case errx == nil:
+			return -1 // Numeric identifiers sort before alphanumeric ones
+		case erry == nil:
+			return 1
+		default:
+			if c := strings.Compare(x, y); c != 0 {
+				return c
+			}
+		}
+	}
+	return cmp.Compare(len(a.pre), len(b.pre))
+}
@@ -0,0 +1,95 @@
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestGetTags(t *testing.T) {
+	r, dir := newTestRepo(t)
+	first := commitFile(t, r, dir, "a.txt", "1\n", "one")
+	second := commitFile(t, r, dir, "a.txt", "2\n", "two")
+
+	if _, err := r.CreateTag("v1.0.0", first, nil); err != nil {
+		t.Fatal(err)
+	}
+	tagger := &object.Signature{Name: "Rel", Email: "rel@example.com", When: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)}
+	if _, err := r.CreateTag("v1.10.0", second, &git.CreateTagOptions{Tagger: tagger, Message: "Release 1.10\n"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"v1.2.0", "v1.10.0-rc.1", "nightly"} {
+		if _, err := r.CreateTag(name, first, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tags, err := GetTags(r, TagSortSemver)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	want := "v1.10.0 v1.10.0-rc.1 v1.2.0 v1.0.0 nightly"
+	if got := strings.Join(names, " "); got != want {
+		t.Errorf("semver order = %s; want %s", got, want)
+	}
+
+	annotated := tags[0]
+	if !annotated.Annotated || annotated.Tagger != "Rel" || annotated.Message != "Release 1.10" {
+		t.Errorf("annotated tag = %+v", annotated)
+	}
+	if annotated.Hash != second.String() {
+		t.Errorf("annotated tag target = %s; want peeled commit %s", annotated.Hash, second)
+	}
+	if tags[3].Annotated || tags[3].Hash != first.String() {
+		t.Errorf("lightweight tag = %+v", tags[3])
+	}
+
+	// The annotated tag was made last, the lightweight ones share the commit date
+	SortTags(tags, TagSortDate)
+	if tags[0].Name != "v1.10.0" {
+		t.Errorf("newest tag by date = %s; want v1.10.0", tags[0].Name)
+	}
+}
+
+func TestCompareSemver(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.0.0", "1.0.0", 0},
+		{"v2", "1.9.9", 1},
+		{"1.0.0-alpha", "1.0.0", -1},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+		{"1.0.0-alpha.beta", "1.0.0-alpha.1", 1},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0+build.5", "1.0.0", 0},
+	}
+
+	for _, tt := range tests {
+		a, ok := parseSemver(tt.a)
+		if !ok {
+			t.Fatalf("parseSemver(%q) failed", tt.a)
+		}
+		b, ok := parseSemver(tt.b)
+		if !ok {
+			t.Fatalf("parseSemver(%q) failed", tt.b)
+		}
+		if got := compareSemver(a, b); got != tt.want {
+			t.Errorf("compareSemver(%s, %s) = %d; want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"release", "1.2.3.4", "v1.x", "1.0-"} {
+		if _, ok := parseSemver(bad); ok {
+			t.Errorf("parseSemver(%q) accepted a non-version", bad)
+		}
+	}
+}
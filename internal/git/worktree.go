@@ -0,0 +1,156 @@
+package git
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+)
+
+// readWorktreeFile returns the content and mode of a worktree file.
+// ok is false when the file does not exist.
+func readWorktreeFile(w *git.Worktree, name string) (content []byte, mode filemode.FileMode, ok bool, err error) {
+	fi, err := w.Filesystem.Lstat(name)
+	if os.IsNotExist(err) {
+		return nil, filemode.Empty, false, nil
+	}
+	if err != nil {
+		return nil, filemode.Empty, false, err
+	}
+	if fi.IsDir() {
+		return nil, filemode.Empty, false, nil
+	}
+
+	if fi.Mode()&os.ModeSymlink != 0 {
+		target, err := w.Filesystem.Readlink(name)
+		if err != nil {
+			return nil, filemode.Empty, false, err
+		}
+		return []byte(target), filemode.Symlink, true, nil
+	}
+
+	f, err := w.Filesystem.Open(name)
+	if err != nil {
+		return nil, filemode.Empty, false, err
+	}
+	defer f.Close()
+
+	content, err = io.ReadAll(f)
+	if err != nil {
+		return nil, filemode.Empty, false, err
+	}
+
+	mode, err = filemode.NewFromOSFileMode(fi.Mode())
+	if err != nil {
+		mode = filemode.Regular
+	}
+	return content, mode, true, nil
+}
+
+// writeWorktreeFile creates or replaces a worktree file, creating parent directories
+func writeWorktreeFile(w *git.Worktree, name string, content []byte, mode filemode.FileMode) error {
+	fs := w.Filesystem
+	if dir := parentDir(name); dir != "" {
+		if err := fs.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+
+	if mode == filemode.Symlink {
+		if err := fs.Remove(name); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return fs.Symlink(string(content), name)
+	}
+
+	perm := os.FileMode(0644)
+	if mode == filemode.Executable {
+		perm = 0755
+	}
+
+	f, err := fs.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// OpenFile keeps the permissions of an existing file
+	if ch, ok := fs.(billy.Change); ok {
+		return ch.Chmod(name, perm)
+	}
+	return nil
+}
+
+// removeWorktreeFile deletes a worktree file and any directories it leaves empty
+func removeWorktreeFile(w *git.Worktree, name string) error {
+	fs := w.Filesystem
+	if err := fs.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for dir := parentDir(name); dir != ""; dir = parentDir(dir) {
+		entries, err := fs.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			break
+		}
+		if err := fs.Remove(dir); err != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// setIndexEntry points the merged index entry for name at a blob, adding it if needed
+func setIndexEntry(idx *index.Index, name string, hash plumbing.Hash, mode filemode.FileMode, size int) {
+	for _, e := range idx.Entries {
+		if e.Name == name && e.Stage == stageMerged {
+			e.Hash = hash
+			e.Mode = mode
+			e.Size = uint32(size)
+			e.ModifiedAt = time.Now()
+			return
+		}
+	}
+
+	e := idx.Add(name)
+	e.Hash = hash
+	e.Mode = mode
+	e.Size = uint32(size)
+	e.ModifiedAt = time.Now()
+}
+
+// removeIndexEntry drops every entry for name, including conflict stages
+func removeIndexEntry(idx *index.Index, name string) {
+	entries := idx.Entries[:0]
+	for _, e := range idx.Entries {
+		if e.Name != name {
+			entries = append(entries, e)
+		}
+	}
+	idx.Entries = entries
+}
+
+// isBinary uses the same heuristic as git: a NUL byte early in the content
+func isBinary(content []byte) bool {
+	n := len(content)
+	if n > 8000 {
+		n = 8000
+	}
+	for _, b := range content[:n] {
+		if b == 0 {
+			return true
+		}
+	}
+	return false
+}
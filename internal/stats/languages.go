@@ -43,24 +43,8 @@ var extToLang = map[string]string{
 	// Add more as needed
 }
 
-// CalculateStats analyzes the repository content (simplified version)
-func CalculateStats(r *gogit.Repository, branchName string) (*ProjectStats, error) {
-	var hash plumbing.Hash
-
-	if branchName == "" {
-		head, err := r.Head()
-		if err != nil {
-			return nil, err
-		}
-		hash = head.Hash()
-	} else {
-		ref, err := r.Reference(plumbing.ReferenceName("refs/heads/"+branchName), true)
-		if err != nil {
-			return nil, err
-		}
-		hash = ref.Hash()
-	}
-
+// CalculateStats analyzes the repository content at the given commit (simplified version)
+func CalculateStats(r *gogit.Repository, hash plumbing.Hash) (*ProjectStats, error) {
 	commit, err := r.CommitObject(hash)
 	if err != nil {
 		return nil, err
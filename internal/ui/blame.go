@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// blameAgeColors runs from the oldest lines of a file to the newest
+var blameAgeColors = []lipgloss.Color{"60", "67", "73", "108", "143", "179", "215", "209"}
+
+// BlameModel is the full-screen blame of a file, one commit annotation per
+// block of lines that came from the same commit
+type BlameModel struct {
+	Blame    *git.Blame
+	Label    string // Revision the user asked for, e.g. a branch name
+	Selected int
+	width    int
+	height   int
+}
+
+type blameMsg struct {
+	Label string
+	Blame *git.Blame
+}
+
+func blameCmd(repoPath, rev, file string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		hash, err := git.ResolveRevision(r, rev)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		blame, err := git.GetBlame(r, file, hash)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return blameMsg{Label: rev, Blame: blame}
+	}
+}
+
+func NewBlameModel(label string, blame *git.Blame, width, height int) BlameModel {
+	return BlameModel{
+		Blame:  blame,
+		Label:  label,
+		width:  width,
+		height: height,
+	}
+}
+
+func (m *BlameModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m BlameModel) rows() int {
+	return max(m.height-6, 1)
+}
+
+// SelectedLine returns the line under the cursor, if any
+func (m BlameModel) SelectedLine() (git.BlameLine, bool) {
+	if m.Blame == nil || m.Selected < 0 || m.Selected >= len(m.Blame.Lines) {
+		return git.BlameLine{}, false
+	}
+	return m.Blame.Lines[m.Selected], true
+}
+
+func (m BlameModel) Update(msg tea.KeyMsg) BlameModel {
+	if m.Blame == nil {
+		return m
+	}
+	lines := m.Blame.Lines
+	last := len(lines) - 1
+
+	switch msg.String() {
+	case "up", "k":
+		m.Selected--
+	case "down", "j":
+		m.Selected++
+	case "pgup", "b":
+		m.Selected -= m.rows()
+	case "pgdown", "f", " ":
+		m.Selected += m.rows()
+	case "home", "g":
+		m.Selected = 0
+	case "end", "G":
+		m.Selected = last
+	case "n":
+		// Start of the next block from another commit
+		for i := m.Selected + 1; i <= last; i++ {
+			if lines[i].Hash != lines[i-1].Hash {
+				m.Selected = i
+				break
+			}
+		}
+	case "N":
+		// Start of the current block, or of the previous one when already there
+		i := m.Selected
+		if i > 0 && lines[i-1].Hash != lines[i].Hash {
+			i--
+		}
+		for i > 0 && lines[i-1].Hash == lines[i].Hash {
+			i--
+		}
+		m.Selected = i
+	}
+	m.Selected = max(min(m.Selected, last), 0)
+	return m
+}
+
+// ageColor places when between the oldest and newest line of the file
+func (m BlameModel) ageColor(when time.Time) lipgloss.Color {
+	span := m.Blame.Newest.Sub(m.Blame.Oldest)
+	if span <= 0 {
+		return blameAgeColors[len(blameAgeColors)-1]
+	}
+	frac := float64(when.Sub(m.Blame.Oldest)) / float64(span)
+	return blameAgeColors[int(frac*float64(len(blameAgeColors)-1)+0.5)]
+}
+
+func (m BlameModel) View() string {
+	var s strings.Builder
+
+	if m.Blame == nil {
+		return StyleDim.Render("   Nothing to blame")
+	}
+
+	s.WriteString(StyleTitle.Render("Blame " + m.Blame.Path))
+	s.WriteString("\n")
+	s.WriteString(StyleDim.Render(fmt.Sprintf(" at %s • %d lines", inspectLabel(m.Label), len(m.Blame.Lines))))
+	s.WriteString("\n\n")
+
+	rows := m.rows()
+	top := 0
+	if m.Selected >= rows {
+		top = m.Selected - rows + 1
+	}
+	end := min(top+rows, len(m.Blame.Lines))
+
+	for i := top; i < end; i++ {
+		s.WriteString(m.renderLine(i, i == top))
+		s.WriteString("\n")
+	}
+	for i := end - top; i < rows; i++ {
+		s.WriteString("\n")
+	}
+
+	footer := "'↑/↓' select, 'n/N' next/previous commit, 'Enter' open commit, 'Esc' back"
+	if l, ok := m.SelectedLine(); ok {
+		footer = fmt.Sprintf("%s %s <%s> • %s", l.Hash[:7], l.Author, l.AuthorEmail, footer)
+	}
+	s.WriteString(StyleDim.Render("\n " + footer))
+	return s.String()
+}
+
+// renderLine draws one line; the commit is only named on the first line of
+// its block (or the first line on screen) to keep the file readable
+func (m BlameModel) renderLine(i int, firstOnScreen bool) string {
+	lines := m.Blame.Lines
+	l := lines[i]
+	age := lipgloss.NewStyle().Foreground(m.ageColor(l.When))
+
+	annotation := strings.Repeat(" ", 39)
+	if firstOnScreen || lines[i-1].Hash != l.Hash {
+		annotation = fmt.Sprintf("%s %s %s",
+			l.Hash[:7],
+			lipgloss.NewStyle().Width(16).MaxWidth(16).Render(l.Author),
+			fmt.Sprintf("%-14s", humanize.Time(l.When)))
+	}
+
+	cursor := "  "
+	text := expandTabs(l.Text)
+	if i == m.Selected {
+		cursor = "▶ "
+		text = StyleSelected.Render(text)
+	}
+
+	line := fmt.Sprintf("%s%s %s %s",
+		cursor,
+		age.Render(annotation),
+		StyleDim.Render(fmt.Sprintf("%5d │", i+1)),
+		text,
+	)
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(line)
+}
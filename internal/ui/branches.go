@@ -1,22 +1,31 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
+	gogit "github.com/go-git/go-git/v5"
 	"github.com/sh9336/gitdash/internal/git"
 )
 
 type BranchesModel struct {
-	Branches []git.Branch
-	Selected int
-	Active   bool // Whether this panel is currently active/focused
+	Branches    []git.Branch
+	Remotes     []git.Branch // Remote-tracking branches, listed after the local ones
+	ShowRemotes bool
+	Marked      string                        // Branch marked as the base of a comparison
+	Forecasts   map[string]*git.MergeForecast // Merge forecasts into HEAD by branch, filled lazily
+	Selected    int                           // Index into the visible list: local branches, then remotes if shown
+	Active      bool                          // Whether this panel is currently active/focused
 }
 
-func NewBranchesModel(branches []git.Branch) BranchesModel {
+func NewBranchesModel(branches, remotes []git.Branch) BranchesModel {
 	m := BranchesModel{
 		Branches: branches,
+		Remotes:  remotes,
 		Selected: 0,
 		Active:   false,
 	}
@@ -30,8 +39,40 @@ func NewBranchesModel(branches []git.Branch) BranchesModel {
 	return m
 }
 
+// Len is the number of selectable rows
+func (m BranchesModel) Len() int {
+	if m.ShowRemotes {
+		return len(m.Branches) + len(m.Remotes)
+	}
+	return len(m.Branches)
+}
+
+func (m BranchesModel) branchAt(i int) git.Branch {
+	if i < len(m.Branches) {
+		return m.Branches[i]
+	}
+	return m.Remotes[i-len(m.Branches)]
+}
+
+// SelectedBranch returns the highlighted local or remote-tracking branch
+func (m BranchesModel) SelectedBranch() (git.Branch, bool) {
+	if m.Selected < 0 || m.Selected >= m.Len() {
+		return git.Branch{}, false
+	}
+	return m.branchAt(m.Selected), true
+}
+
+// ToggleRemotes shows or hides the remote-tracking section, moving the
+// cursor back to the local branches if it was inside the hidden section
+func (m *BranchesModel) ToggleRemotes() {
+	m.ShowRemotes = !m.ShowRemotes
+	if m.Selected >= m.Len() {
+		m.Selected = max(m.Len()-1, 0)
+	}
+}
+
 func (m *BranchesModel) Next() {
-	if m.Selected < len(m.Branches)-1 {
+	if m.Selected < m.Len()-1 {
 		m.Selected++
 	}
 }
@@ -42,6 +83,273 @@ func (m *BranchesModel) Previous() {
 	}
 }
 
+// keepForecasts carries over the forecasts from a previous load that are
+// still valid for the same HEAD and branch tips
+func (m *BranchesModel) keepForecasts(old map[string]*git.MergeForecast, head string) {
+	m.Forecasts = make(map[string]*git.MergeForecast)
+	for i := 0; i < len(m.Branches)+len(m.Remotes); i++ {
+		b := m.branchAt(i)
+		if f, ok := old[b.Name]; ok && f.Ours == head && f.Theirs == b.Hash {
+			m.Forecasts[b.Name] = f
+		}
+	}
+}
+
+// branchOpDoneMsg carries the outcome of a branch write for the status line
+type branchOpDoneMsg struct {
+	Message string
+	Inspect string // Revision to inspect afterwards when the inspected branch moved, if any
+}
+
+// branchUnmergedMsg asks again before deleting a branch that would lose commits
+type branchUnmergedMsg struct {
+	Err     *git.UnmergedError
+	Inspect string
+}
+
+// branchOpCmd runs a branch write and reports message when it succeeds
+func branchOpCmd(path, message, inspect string, op func(r *gogit.Repository) error) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		if err := op(r); err != nil {
+			return errMsg(err)
+		}
+
+		return branchOpDoneMsg{Message: message, Inspect: inspect}
+	}
+}
+
+func deleteBranchCmd(path, name, inspect string, force bool) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		var unmerged *git.UnmergedError
+		err = git.DeleteBranch(r, name, force)
+		if errors.As(err, &unmerged) {
+			return branchUnmergedMsg{Err: unmerged, Inspect: inspect}
+		}
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return branchOpDoneMsg{Message: "Deleted branch " + name, Inspect: inspect}
+	}
+}
+
+// unmergedConfirm is the second question before losing commits with a branch
+func (m Model) unmergedConfirm(msg branchUnmergedMsg) ConfirmModel {
+	e := msg.Err
+	confirm := NewConfirmModel(
+		fmt.Sprintf("%s is not merged anywhere, delete it and lose %d commit(s)?", e.Branch, len(e.Commits)),
+		deleteBranchCmd(m.RepoInfo.Path, e.Branch, msg.Inspect, true))
+	for _, c := range e.Commits {
+		confirm.Details = append(confirm.Details, fmt.Sprintf("%s %s %s",
+			StyleSelected.Render(c.Hash[:7]),
+			strings.Split(c.Message, "\n")[0],
+			StyleDim.Render(fmt.Sprintf("(%s, %s)", c.Author, humanize.Time(c.When)))))
+	}
+	return confirm
+}
+
+// selectedLocalBranch returns the highlighted branch if it can be changed
+func (m *Model) selectedLocalBranch() (git.Branch, bool) {
+	b, ok := m.BranchesModel.SelectedBranch()
+	if ok && b.IsRemote {
+		m.StatusMessage = fmt.Sprintf("%s is a remote-tracking branch; it can only be inspected", b.Name)
+		return b, false
+	}
+	return b, ok
+}
+
+// handleBranchesKey runs the Branches panel bindings that open views or
+// change branches; ok is false for keys it does not own
+func (m Model) handleBranchesKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "m":
+		b, ok := m.BranchesModel.SelectedBranch()
+		if !ok {
+			return m, nil, true
+		}
+		if m.BranchesModel.Marked == b.Name {
+			m.BranchesModel.Marked = ""
+			m.StatusMessage = "Mark cleared"
+		} else {
+			m.BranchesModel.Marked = b.Name
+			m.StatusMessage = fmt.Sprintf("Marked %s, select another branch and press 'c' to compare", b.Name)
+		}
+		return m, nil, true
+	case "c":
+		b, ok := m.BranchesModel.SelectedBranch()
+		if !ok {
+			return m, nil, true
+		}
+		base := m.BranchesModel.Marked
+		if base == "" {
+			m.StatusMessage = "Mark a branch with 'm' first, then select the one to compare it with"
+			return m, nil, true
+		}
+		if base == b.Name {
+			m.StatusMessage = "Select a different branch than the marked one"
+			return m, nil, true
+		}
+		m.StatusMessage = fmt.Sprintf("Comparing %s with %s...", base, b.Name)
+		return m, compareCmd(m.RepoInfo.Path, base, b.Name), true
+	case "o", "O":
+		b, ok := m.BranchesModel.SelectedBranch()
+		if !ok {
+			return m, nil, true
+		}
+		if b.IsRemote {
+			m.StatusMessage = fmt.Sprintf("%s is a remote-tracking branch; it can only be inspected", b.Name)
+			return m, nil, true
+		}
+		if b.IsCurrent {
+			m.StatusMessage = fmt.Sprintf("Already on %s", b.Name)
+			return m, nil, true
+		}
+		if m, blocked := m.blockedByOperation("check out " + b.Name); blocked {
+			return m, nil, true
+		}
+		if msg.String() == "O" {
+			m, cmd := m.startCheckout(b.Name, fmt.Sprintf("Stashing and checking out %s...", b.Name),
+				stashCheckoutCmd(m.RepoInfo.Path, b.Name, stashUntracked(m.Config)))
+			return m, cmd, true
+		}
+		m, cmd := m.startCheckout(b.Name, fmt.Sprintf("Checking out %s...", b.Name), safeCheckoutCmd(m.RepoInfo.Path, b.Name))
+		return m, cmd, true
+	case "n":
+		if m, blocked := m.blockedByOperation("create a branch"); blocked {
+			return m, nil, true
+		}
+		rev := m.InspectedBranch
+		m.Prompt = NewPromptModel(fmt.Sprintf("New branch at %s:", inspectLabel(rev)), "", func(name string) tea.Cmd {
+			if name == "" {
+				return nil
+			}
+			return branchOpCmd(m.RepoInfo.Path, fmt.Sprintf("Created %s at %s", name, inspectLabel(rev)), "", func(r *gogit.Repository) error {
+				return git.CreateBranch(r, name, rev)
+			})
+		})
+		return m, nil, true
+	case "e":
+		b, ok := m.selectedLocalBranch()
+		if !ok {
+			return m, nil, true
+		}
+		if m, blocked := m.blockedByOperation("rename " + b.Name); blocked {
+			return m, nil, true
+		}
+		inspected := m.InspectedBranch
+		m.Prompt = NewPromptModel(fmt.Sprintf("Rename %s to:", b.Name), b.Name, func(name string) tea.Cmd {
+			if name == "" || name == b.Name {
+				return nil
+			}
+			inspect := ""
+			if inspected == b.Name {
+				inspect = name
+			}
+			return branchOpCmd(m.RepoInfo.Path, fmt.Sprintf("Renamed %s to %s", b.Name, name), inspect, func(r *gogit.Repository) error {
+				return git.RenameBranch(r, b.Name, name)
+			})
+		})
+		return m, nil, true
+	case "D":
+		b, ok := m.selectedLocalBranch()
+		if !ok {
+			return m, nil, true
+		}
+		if b.IsCurrent {
+			m.StatusMessage = fmt.Sprintf("%s is the current branch, check out another one first", b.Name)
+			return m, nil, true
+		}
+		if m, blocked := m.blockedByOperation("delete " + b.Name); blocked {
+			return m, nil, true
+		}
+		inspect := ""
+		if m.InspectedBranch == b.Name {
+			inspect = m.RepoInfo.CurrentBranch
+		}
+		m.Confirm = NewConfirmModel(fmt.Sprintf("Delete branch %s?", b.Name), deleteBranchCmd(m.RepoInfo.Path, b.Name, inspect, false))
+		return m, nil, true
+	case "u":
+		b, ok := m.selectedLocalBranch()
+		if !ok {
+			return m, nil, true
+		}
+		if m, blocked := m.blockedByOperation("change the upstream of " + b.Name); blocked {
+			return m, nil, true
+		}
+		m.Prompt = NewPromptModel(fmt.Sprintf("Upstream of %s (empty to unset):", b.Name), b.Upstream, func(upstream string) tea.Cmd {
+			if upstream == "" {
+				if !b.HasUpstream() {
+					return nil
+				}
+				return branchOpCmd(m.RepoInfo.Path, fmt.Sprintf("%s no longer tracks %s", b.Name, b.Upstream), "", func(r *gogit.Repository) error {
+					return git.UnsetUpstream(r, b.Name)
+				})
+			}
+			return branchOpCmd(m.RepoInfo.Path, fmt.Sprintf("%s now tracks %s", b.Name, upstream), "", func(r *gogit.Repository) error {
+				return git.SetUpstream(r, b.Name, upstream)
+			})
+		})
+		return m, nil, true
+	case "C":
+		if m, blocked := m.blockedByOperation("clean up branches"); blocked {
+			return m, nil, true
+		}
+		base, staleDays := cleanupSettings(m.Config)
+		m.StatusMessage = "Looking for branches to clean up..."
+		return m, cleanupCmd(m.RepoInfo.Path, base, staleDays), true
+	case "M":
+		b, ok := m.BranchesModel.SelectedBranch()
+		if !ok {
+			return m, nil, true
+		}
+		if b.IsCurrent {
+			m.StatusMessage = fmt.Sprintf("%s is the current branch, select another to forecast merging it", b.Name)
+			return m, nil, true
+		}
+		m.StatusMessage = fmt.Sprintf("Forecasting merge of %s...", b.Name)
+		return m, m.forecastSelected(true), true
+	}
+	return m, nil, false
+}
+
+// trackingInfo renders the upstream like `git branch -vv`: [origin/main ↑3 ↓1]
+func trackingInfo(b git.Branch) string {
+	if !b.HasUpstream() {
+		return ""
+	}
+
+	var parts []string
+	if b.UpstreamGone {
+		parts = append(parts, lipgloss.NewStyle().Foreground(ColorError).Render("gone"))
+	} else {
+		if b.Ahead > 0 {
+			parts = append(parts, lipgloss.NewStyle().Foreground(ColorSuccess).Render(fmt.Sprintf("↑%d", b.Ahead)))
+		}
+		if b.Behind > 0 {
+			parts = append(parts, lipgloss.NewStyle().Foreground(ColorWarning).Render(fmt.Sprintf("↓%d", b.Behind)))
+		}
+	}
+
+	info := " " + StyleDim.Render("["+b.Upstream)
+	if b.UpstreamGone {
+		info += StyleDim.Render(":")
+	}
+	if len(parts) > 0 {
+		info += " " + strings.Join(parts, " ")
+	}
+	return info + StyleDim.Render("]")
+}
+
 func (m BranchesModel) View(width int, loading bool, checkingOut string, spinner int) string {
 	var s strings.Builder
 
@@ -53,66 +361,93 @@ func (m BranchesModel) View(width int, loading bool, checkingOut string, spinner
 	} else {
 		s.WriteString(StyleHeader.Render(title))
 	}
+	if len(m.Remotes) > 0 && !m.ShowRemotes {
+		s.WriteString(StyleDim.Render(fmt.Sprintf("  + %d remote-tracking (R to show)", len(m.Remotes))))
+	}
 	s.WriteString("\n")
 
-	// Limit number of branches shown to fit in panel roughly, or just show all (viewport handles scrolling main area)
-	// But wait, the Dashboard Viewport handles the *whole* content.
-	// If the list is huge, we might need internal paging, but for now let's rely on the main viewport or just show top N.
-	// Actually, if we are navigating *inside* this panel, we need to ensure the selected item is visible if we implement scrolling *inside* the panel.
-	// For now, let's just render all and let the user scroll the whole dashboard?
-	// No, if I press Down, I expect the selection to move.
-
-	// Let's render all for now.
-	spinnerChars := []string{"⠋", "⠙", "⠹", "⠸"}
+	// Every row is rendered; the dashboard viewport scrolls the whole page
 	for i, b := range m.Branches {
-		// Add spinner if this branch is being checked out
-		spinnerPrefix := ""
-		if loading && checkingOut == b.Name {
-			spinnerPrefix = spinnerChars[spinner%4] + " "
-		}
-
-		cursor := "  "
-		if b.IsCurrent {
-			cursor = " ●" // Bullet for HEAD
-		}
+		s.WriteString(m.renderBranch(i, b, loading, checkingOut, spinner) + "\n")
+	}
 
-		// Visual indicator for selection cursor
-		if m.Active && i == m.Selected {
-			cursor = " ▶" // Selection arrow
-			if b.IsCurrent {
-				cursor = " ★" // Star for both selection and HEAD
+	if m.ShowRemotes && len(m.Remotes) > 0 {
+		remote := ""
+		for i, b := range m.Remotes {
+			if b.Remote != remote {
+				remote = b.Remote
+				s.WriteString(StyleHeader.Render("   remotes/"+remote) + "\n")
 			}
+			s.WriteString(m.renderBranch(len(m.Branches)+i, b, loading, checkingOut, spinner) + "\n")
 		}
+	}
 
-		nameStyle := StyleNormal
-		if b.IsCurrent {
-			nameStyle = StyleSelected // Current branch in green/bold
-		}
+	style := StylePanel.Copy().Width(width)
+	if m.Active {
+		style = style.BorderForeground(ColorPrimary)
+	}
+
+	return style.Render(s.String())
+}
 
-		// Highlight the entire line if selected and active
-		lineContent := b.Name
+// renderBranch renders row i of the visible list
+func (m BranchesModel) renderBranch(i int, b git.Branch, loading bool, checkingOut string, spinner int) string {
+	spinnerChars := []string{"⠋", "⠙", "⠹", "⠸"}
+
+	// Add spinner if this branch is being checked out
+	spinnerPrefix := ""
+	if loading && checkingOut == b.Name {
+		spinnerPrefix = spinnerChars[spinner%4] + " "
+	}
+
+	cursor := "  "
+	if b.IsCurrent {
+		cursor = " ●" // Bullet for HEAD
+	}
+
+	// Visual indicator for selection cursor
+	if m.Active && i == m.Selected {
+		cursor = " ▶" // Selection arrow
 		if b.IsCurrent {
-			lineContent = "HEAD → " + b.Name
+			cursor = " ★" // Star for both selection and HEAD
 		}
+	}
 
-		timeStr := humanize.Time(b.LastCommit)
-		rightContent := fmt.Sprintf(" (%s)", timeStr)
+	nameStyle := StyleNormal
+	if b.IsCurrent {
+		nameStyle = StyleSelected // Current branch in green/bold
+	} else if b.IsRemote {
+		nameStyle = StyleDim
+	}
 
-		mainText := nameStyle.Render(lineContent)
-		if m.Active && i == m.Selected {
-			mainText = StyleSelected.Copy().Underline(true).Render(lineContent)
-		}
+	lineContent := b.Name
+	if b.IsCurrent {
+		lineContent = "HEAD → " + b.Name
+	}
 
-		line := fmt.Sprintf(" %s%s %s", spinnerPrefix, cursor, mainText)
-		line += StyleDim.Render(rightContent)
+	indent := ""
+	if b.IsRemote {
+		indent = "  " // Nest under the remote heading
+	}
+
+	timeStr := humanize.Time(b.LastCommit)
+	rightContent := fmt.Sprintf(" (%s)", timeStr)
 
-		s.WriteString(line + "\n")
+	// Highlight the entire line if selected and active
+	mainText := nameStyle.Render(lineContent)
+	if m.Active && i == m.Selected {
+		mainText = StyleSelected.Copy().Underline(true).Render(lineContent)
 	}
 
-	style := StylePanel.Copy().Width(width)
-	if m.Active {
-		style = style.BorderForeground(ColorPrimary)
+	if b.Name == m.Marked {
+		mainText += StyleSelected.Render(" ◆")
+	}
+	if f, ok := m.Forecasts[b.Name]; ok && f.Theirs == b.Hash {
+		mainText += forecastBadge(f)
 	}
 
-	return style.Render(s.String())
+	line := fmt.Sprintf(" %s%s%s %s", indent, spinnerPrefix, cursor, mainText)
+	line += trackingInfo(b)
+	line += StyleDim.Render(rightContent)
+	return line
 }
@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sh9336/gitdash/internal/config"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// CheckoutModal is shown when a safe checkout is blocked by local changes.
+// It lists what would be lost and lets the user cancel, stash or force.
+type CheckoutModal struct {
+	Plan *git.CheckoutPlan
+}
+
+type checkoutBlockedMsg struct {
+	Plan *git.CheckoutPlan
+}
+
+func (c CheckoutModal) Active() bool {
+	return c.Plan != nil
+}
+
+// safeCheckoutCmd switches branches only when no local change is in the way
+func safeCheckoutCmd(path, branchName string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		plan, err := git.Checkout(r, branchName)
+		if errors.Is(err, git.ErrWouldOverwrite) {
+			return checkoutBlockedMsg{Plan: plan}
+		}
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return checkoutDoneMsg{Path: path, Target: branchName}
+	}
+}
+
+// stashCheckoutCmd carries the local changes over to branchName through a
+// stash. An untracked file in the way that may not be stashed opens the modal.
+func stashCheckoutCmd(path, branchName string, includeUntracked bool) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		result, err := git.StashCheckout(r, branchName, includeUntracked)
+		if errors.Is(err, git.ErrWouldOverwrite) && result != nil {
+			return checkoutBlockedMsg{Plan: result.Plan}
+		}
+		if err != nil {
+			return errMsg(err)
+		}
+
+		note := ""
+		if len(result.Conflicts) > 0 {
+			note = fmt.Sprintf("your changes conflict in %s and were kept in stash@{0}", summarizePaths(result.Conflicts))
+		} else if result.Stashed {
+			note = "local changes reapplied"
+		}
+
+		return checkoutDoneMsg{Path: path, Target: branchName, Note: note}
+	}
+}
+
+// stashUntracked reports whether auto-stash checkouts carry untracked files
+func stashUntracked(cfg *config.Config) bool {
+	return cfg != nil && cfg.Checkout.StashUntracked
+}
+
+// startCheckout shows the spinner while cmd switches to branchName
+func (m Model) startCheckout(branchName, status string, cmd tea.Cmd) (Model, tea.Cmd) {
+	m.Loading = true
+	m.StatusMessage = status
+	m.CheckingOut = branchName
+	m.RefreshTries = 0
+	m.Spinner = 0
+	m.Viewport.SetContent(m.RenderMainContent())
+	return m, tea.Batch(
+		tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg { return checkoutTickMsg{} }),
+		cmd,
+	)
+}
+
+// updateCheckoutModal answers the blocked checkout modal
+func (m Model) updateCheckoutModal(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	plan := m.Checkout.Plan
+	switch msg.String() {
+	case "ctrl+c":
+		m.Quitting = true
+		return m, tea.Quit
+	case "c", "esc", "q":
+		m.Checkout = CheckoutModal{}
+		m.StatusMessage = "Checkout cancelled"
+		return m, nil
+	case "s":
+		m.Checkout = CheckoutModal{}
+		return m.startCheckout(plan.Branch, fmt.Sprintf("Stashing and checking out %s...", plan.Branch),
+			stashCheckoutCmd(m.RepoInfo.Path, plan.Branch, stashUntracked(m.Config) || len(plan.Untracked) > 0))
+	case "f":
+		m.Checkout = CheckoutModal{}
+		return m.startCheckout(plan.Branch, fmt.Sprintf("Force checking out %s...", plan.Branch),
+			checkoutCmd(m.RepoInfo.Path, plan.Branch, true))
+	}
+	return m, nil
+}
+
+func (m Model) checkoutView() string {
+	plan := m.Checkout.Plan
+
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(ColorWarning).Render("Checkout " + plan.Branch + " would overwrite local changes"))
+	s.WriteString("\n\n")
+
+	list := func(title string, paths []string) {
+		if len(paths) == 0 {
+			return
+		}
+		s.WriteString(StyleHeader.Render(fmt.Sprintf("%s (%d)", title, len(paths))))
+		s.WriteString("\n")
+		shown := min(len(paths), 8)
+		for _, p := range paths[:shown] {
+			s.WriteString("  " + lipgloss.NewStyle().Foreground(ColorError).Render("✗") + " " + p + "\n")
+		}
+		if len(paths) > shown {
+			s.WriteString(StyleDim.Render(fmt.Sprintf("  ... and %d more", len(paths)-shown)) + "\n")
+		}
+		s.WriteString("\n")
+	}
+	list("Modified", plan.Overwritten)
+	list("Untracked", plan.Untracked)
+
+	key := lipgloss.NewStyle().Foreground(ColorInfo).Bold(true)
+	s.WriteString(fmt.Sprintf("%s cancel   %s auto-stash and reapply   %s force, discard them",
+		key.Render("c"), key.Render("s"), key.Render("f")))
+
+	box := lipgloss.NewStyle().
+		Width(min(72, max(m.Width-4, 20))).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorWarning).
+		Padding(1, 2).
+		Render(s.String())
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, box)
+}
@@ -0,0 +1,285 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/sh9336/gitdash/internal/config"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// CleanupModel is the full-screen list of branches that look safe to
+// delete: merged into the base, stale, or tracking a deleted upstream
+type CleanupModel struct {
+	Base       string // Empty means the current branch
+	StaleDays  int
+	Candidates []git.CleanupCandidate
+	Picked     map[string]bool     // Branches to delete in the next batch
+	Undo       []git.DeletedBranch // Last deleted batch, restored with 'u'
+	Summary    string              // Outcome of the last batch
+	Selected   int
+	width      int
+	height     int
+}
+
+type cleanupMsg struct {
+	Candidates []git.CleanupCandidate
+}
+
+// cleanupDoneMsg reports a deleted or restored batch
+type cleanupDoneMsg struct {
+	Deleted  []git.DeletedBranch
+	Restored []git.DeletedBranch
+	Err      error // A batch can fail halfway, Deleted still lists what went
+}
+
+func cleanupCmd(repoPath, base string, staleDays int) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		stale := time.Duration(staleDays) * 24 * time.Hour
+		candidates, err := git.FindCleanupCandidates(r, base, stale, time.Now())
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return cleanupMsg{Candidates: candidates}
+	}
+}
+
+func cleanupDeleteCmd(repoPath string, names []string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		deleted, err := git.DeleteBranches(r, names)
+		return cleanupDoneMsg{Deleted: deleted, Err: err}
+	}
+}
+
+func cleanupRestoreCmd(repoPath string, deleted []git.DeletedBranch) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		if err := git.RestoreBranches(r, deleted); err != nil {
+			return errMsg(err)
+		}
+
+		return cleanupDoneMsg{Restored: deleted}
+	}
+}
+
+// cleanupSettings returns the configured base branch and stale period
+func cleanupSettings(cfg *config.Config) (string, int) {
+	if cfg == nil {
+		return "", 90
+	}
+	return cfg.Cleanup.Base, cfg.Cleanup.StaleDays
+}
+
+func NewCleanupModel(base string, staleDays int, width, height int) CleanupModel {
+	return CleanupModel{
+		Base:      base,
+		StaleDays: staleDays,
+		Picked:    make(map[string]bool),
+		width:     width,
+		height:    height,
+	}
+}
+
+// SetCandidates replaces the list, keeping the picks that are still listed
+func (m *CleanupModel) SetCandidates(candidates []git.CleanupCandidate) {
+	m.Candidates = candidates
+	picked := make(map[string]bool)
+	for _, c := range candidates {
+		if m.Picked[c.Name] {
+			picked[c.Name] = true
+		}
+	}
+	m.Picked = picked
+	m.Selected = max(min(m.Selected, len(candidates)-1), 0)
+}
+
+func (m *CleanupModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m CleanupModel) rows() int {
+	return max(m.height-7, 1)
+}
+
+// Batch is what the next delete covers: the picked branches, or the
+// highlighted one when nothing is picked
+func (m CleanupModel) Batch() []git.CleanupCandidate {
+	var batch []git.CleanupCandidate
+	for _, c := range m.Candidates {
+		if m.Picked[c.Name] {
+			batch = append(batch, c)
+		}
+	}
+	if len(batch) == 0 && m.Selected < len(m.Candidates) {
+		batch = append(batch, m.Candidates[m.Selected])
+	}
+	return batch
+}
+
+func (m CleanupModel) Update(msg tea.KeyMsg) CleanupModel {
+	last := len(m.Candidates) - 1
+	switch msg.String() {
+	case "up", "k":
+		m.Selected--
+	case "down", "j":
+		m.Selected++
+	case "pgup", "b":
+		m.Selected -= m.rows()
+	case "pgdown", "f":
+		m.Selected += m.rows()
+	case "home", "g":
+		m.Selected = 0
+	case "end", "G":
+		m.Selected = last
+	case " ":
+		if m.Selected <= last {
+			name := m.Candidates[m.Selected].Name
+			if m.Picked[name] {
+				delete(m.Picked, name)
+			} else {
+				m.Picked[name] = true
+			}
+			m.Selected++
+		}
+	case "a":
+		// Pick everything, or nothing when everything is picked already
+		all := len(m.Picked) < len(m.Candidates)
+		m.Picked = make(map[string]bool)
+		if all {
+			for _, c := range m.Candidates {
+				m.Picked[c.Name] = true
+			}
+		}
+	}
+	m.Selected = max(min(m.Selected, last), 0)
+	return m
+}
+
+// deleteBatchConfirm lists a batch before it is deleted
+func deleteBatchConfirm(repoPath string, batch []git.CleanupCandidate) ConfirmModel {
+	names := make([]string, len(batch))
+	details := make([]string, len(batch))
+	for i, c := range batch {
+		names[i] = c.Name
+		details[i] = fmt.Sprintf("%s %s", StyleSelected.Render(c.Name),
+			StyleDim.Render(fmt.Sprintf("(%s, %s)", candidateReasons(c), humanize.Time(c.LastCommit))))
+	}
+
+	confirm := NewConfirmModel(fmt.Sprintf("Delete %d branch(es)? They are recorded in .git/gitdash-cleanup.log", len(batch)),
+		cleanupDeleteCmd(repoPath, names))
+	confirm.Details = details
+	return confirm
+}
+
+// candidateReasons lists why a branch is a candidate
+func candidateReasons(c git.CleanupCandidate) string {
+	var reasons []string
+	if c.Merged {
+		reasons = append(reasons, "merged")
+	}
+	if c.Stale {
+		reasons = append(reasons, "stale")
+	}
+	if c.Gone {
+		reasons = append(reasons, "gone")
+	}
+	return strings.Join(reasons, " ")
+}
+
+func (m CleanupModel) View() string {
+	var s strings.Builder
+
+	base := m.Base
+	if base == "" {
+		base = "the current branch"
+	}
+	s.WriteString(StyleTitle.Render("Branch cleanup"))
+	s.WriteString("\n")
+	stale := "stale check off"
+	if m.StaleDays > 0 {
+		stale = fmt.Sprintf("no commit in %d days", m.StaleDays)
+	}
+	s.WriteString(StyleDim.Render(fmt.Sprintf(" merged into %s, %s, or upstream gone • %d candidates, %d picked",
+		base, stale, len(m.Candidates), len(m.Picked))))
+	s.WriteString("\n")
+	if m.Summary != "" {
+		s.WriteString(" " + m.Summary)
+	}
+	s.WriteString("\n\n")
+
+	rows := m.rows()
+	if len(m.Candidates) == 0 {
+		s.WriteString(StyleDim.Render("   Nothing to clean up"))
+		s.WriteString("\n")
+		rows--
+	}
+	top := 0
+	if m.Selected >= rows {
+		top = m.Selected - rows + 1
+	}
+	end := min(top+rows, len(m.Candidates))
+	for i := top; i < end; i++ {
+		s.WriteString(m.renderCandidate(i))
+		s.WriteString("\n")
+	}
+	for i := end - top; i < rows; i++ {
+		s.WriteString("\n")
+	}
+
+	footer := "\n '↑/↓' select, 'Space' pick, 'a' pick all/none, 'd' delete picked"
+	if len(m.Undo) > 0 {
+		footer += ", 'u' undo last delete"
+	}
+	s.WriteString(StyleDim.Render(footer + ", 'Esc' back"))
+	return s.String()
+}
+
+func (m CleanupModel) renderCandidate(i int) string {
+	c := m.Candidates[i]
+
+	cursor := "  "
+	nameStyle := StyleNormal
+	if i == m.Selected {
+		cursor = "▶ "
+		nameStyle = StyleSelected.Copy().Underline(true)
+	}
+	box := StyleDim.Render("[ ]")
+	if m.Picked[c.Name] {
+		box = lipgloss.NewStyle().Foreground(ColorError).Render("[x]")
+	}
+
+	reasonStyle := lipgloss.NewStyle().Foreground(ColorWarning)
+	if c.Merged {
+		reasonStyle = lipgloss.NewStyle().Foreground(ColorSuccess)
+	}
+
+	line := fmt.Sprintf("%s%s %s %s %s %s",
+		cursor,
+		box,
+		nameStyle.Render(fmt.Sprintf("%-32s", c.Name)),
+		reasonStyle.Render(fmt.Sprintf("%-18s", candidateReasons(c))),
+		lipgloss.NewStyle().Foreground(ColorInfo).Width(16).MaxWidth(16).Render(c.Author),
+		StyleDim.Render(humanize.Time(c.LastCommit)),
+	)
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(line)
+}
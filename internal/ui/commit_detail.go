@@ -0,0 +1,171 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// CommitDetailModel is the full-screen view of a single commit
+type CommitDetailModel struct {
+	Detail   *git.CommitDetail
+	Diff     []git.FileDiff
+	Viewport viewport.Model
+}
+
+type commitDetailMsg struct {
+	Detail *git.CommitDetail
+}
+
+func commitDetailCmd(path, hash string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		detail, err := git.GetCommitDetail(r, hash)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return commitDetailMsg{Detail: detail}
+	}
+}
+
+func NewCommitDetailModel(detail *git.CommitDetail, width, height int) CommitDetailModel {
+	m := CommitDetailModel{
+		Detail:   detail,
+		Viewport: viewport.New(0, 0),
+	}
+	if detail != nil {
+		m.Diff = git.FileDiffs(detail.Patch, git.DefaultContext)
+	}
+	m.SetSize(width, height)
+	return m
+}
+
+// SetSize resizes the scrollable area, leaving room for the title and footer
+func (m *CommitDetailModel) SetSize(width, height int) {
+	m.Viewport.Width = width
+	m.Viewport.Height = height - 4
+	if m.Viewport.Height < 1 {
+		m.Viewport.Height = 1
+	}
+	m.Viewport.SetContent(m.renderContent(width))
+}
+
+func (m CommitDetailModel) Update(msg tea.Msg) (CommitDetailModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Viewport, cmd = m.Viewport.Update(msg)
+	return m, cmd
+}
+
+func (m CommitDetailModel) renderContent(width int) string {
+	d := m.Detail
+	if d == nil {
+		return StyleDim.Render("   No commit selected")
+	}
+
+	var s strings.Builder
+
+	label := lipgloss.NewStyle().Foreground(ColorSubText).Width(10)
+	person := func(name, email string, when time.Time) string {
+		return fmt.Sprintf("%s <%s>", name, email) + StyleDim.Render("  "+when.Format("Mon Jan 2 15:04:05 2006 -0700"))
+	}
+
+	s.WriteString(" " + label.Render("Commit") + lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render(d.Hash) + "\n")
+	s.WriteString(" " + label.Render("Author") + person(d.Author, d.AuthorEmail, d.AuthorWhen) + StyleDim.Render(" ("+humanize.Time(d.AuthorWhen)+")") + "\n")
+	s.WriteString(" " + label.Render("Committer") + person(d.Committer, d.CommitterEmail, d.CommitterWhen) + StyleDim.Render(" ("+humanize.Time(d.CommitterWhen)+")") + "\n")
+
+	parents := StyleDim.Render("none (root commit)")
+	if len(d.Parents) > 0 {
+		short := make([]string, len(d.Parents))
+		for i, p := range d.Parents {
+			short[i] = p[:7]
+		}
+		parents = strings.Join(short, " ")
+		if len(d.Parents) > 1 {
+			parents += StyleDim.Render("  (merge, diff against first parent)")
+		}
+	}
+	s.WriteString(" " + label.Render("Parents") + parents + "\n\n")
+
+	for _, line := range strings.Split(strings.TrimRight(d.Message, "\n"), "\n") {
+		s.WriteString("    " + StyleNormal.Render(line) + "\n")
+	}
+	s.WriteString("\n")
+
+	s.WriteString(renderFileStats(d.Files))
+
+	s.WriteString(StyleHeader.Render("Diff"))
+	s.WriteString("\n")
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(strings.Repeat("─", width)))
+	s.WriteString("\n")
+	diffLines, _, _ := renderDiff(m.Diff, width, true)
+	s.WriteString(strings.Join(diffLines, "\n"))
+
+	return s.String()
+}
+
+// renderFileStats lists changed files with their +/- counts like `git diff --stat`
+func renderFileStats(files []git.FileStat) string {
+	var s strings.Builder
+
+	added, deleted := 0, 0
+	for _, f := range files {
+		added += f.Additions
+		deleted += f.Deletions
+	}
+	s.WriteString(StyleHeader.Render(fmt.Sprintf("Files (%d)", len(files))))
+	s.WriteString(" " + StyleDiffAdd.Render(fmt.Sprintf("+%d", added)) + " " + StyleDiffDel.Render(fmt.Sprintf("-%d", deleted)))
+	s.WriteString("\n")
+
+	pathWidth := 0
+	for _, f := range files {
+		pathWidth = max(pathWidth, lipgloss.Width(fileStatPath(f)))
+	}
+
+	statusColors := map[string]lipgloss.Color{"A": ColorSuccess, "M": ColorWarning, "D": ColorError, "R": ColorInfo}
+	for _, f := range files {
+		counts := StyleDim.Render("binary")
+		if !f.Binary {
+			counts = StyleDiffAdd.Render(fmt.Sprintf("+%d", f.Additions)) + " " + StyleDiffDel.Render(fmt.Sprintf("-%d", f.Deletions))
+		}
+		s.WriteString(fmt.Sprintf("   %s %-*s  %s\n",
+			lipgloss.NewStyle().Foreground(statusColors[f.Status]).Render(f.Status),
+			pathWidth, fileStatPath(f),
+			counts,
+		))
+	}
+	s.WriteString("\n")
+	return s.String()
+}
+
+func fileStatPath(f git.FileStat) string {
+	if f.OldPath != "" {
+		return f.OldPath + " → " + f.Path
+	}
+	return f.Path
+}
+
+func (m CommitDetailModel) View() string {
+	var s strings.Builder
+
+	title := "Commit"
+	if m.Detail != nil {
+		title += " " + m.Detail.Hash[:7]
+	}
+	s.WriteString(StyleTitle.Render(title))
+	s.WriteString("\n")
+	s.WriteString(m.Viewport.View())
+	s.WriteString(StyleDim.Render(fmt.Sprintf("\n '↑/↓' scroll, 'PgUp/PgDn' page, 'Esc' back • %3.f%%", m.Viewport.ScrollPercent()*100)))
+
+	return s.String()
+}
@@ -0,0 +1,145 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// commitFilterHelp is shown as the placeholder of the filter prompt
+const commitFilterHelp = "words author:re path:dir since:7d until:2024-05-01 msg:/re/"
+
+type commitFilterMsg struct {
+	Filter  string
+	Commits []git.Commit
+	More    bool // The search stopped at the limit, older commits may match too
+}
+
+// parseCommitFilter turns the filter prompt into a query. Free words must
+// all appear in the message; key:value terms narrow further:
+//
+//	author:priya        regex on "Name <email>", case-insensitive
+//	path:internal/billing
+//	since:7d until:2024-05-01
+//	msg:/^fix/          regex on the message
+func parseCommitFilter(text string, now time.Time) (git.CommitQuery, error) {
+	var q git.CommitQuery
+	var words []string
+
+	for _, field := range strings.Fields(text) {
+		key, value, ok := strings.Cut(field, ":")
+		if !ok || value == "" {
+			words = append(words, field)
+			continue
+		}
+
+		var err error
+		switch key {
+		case "author":
+			q.Author, err = regexp.Compile("(?i)" + value)
+		case "path":
+			q.Path = value
+		case "since":
+			q.Since, err = parseFilterDate(value, now)
+		case "until":
+			q.Until, err = parseFilterDate(value, now)
+			if err == nil && len(value) == len("2006-01-02") {
+				q.Until = q.Until.AddDate(0, 0, 1).Add(-time.Second) // Whole day
+			}
+		case "msg":
+			q.MessageRegex, err = regexp.Compile(strings.TrimSuffix(strings.TrimPrefix(value, "/"), "/"))
+		default:
+			words = append(words, field) // e.g. "fix: typo" style subjects
+		}
+		if err != nil {
+			return q, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	q.Message = strings.Join(words, " ")
+	return q, nil
+}
+
+// parseFilterDate accepts today, yesterday, a relative age like 3d, 2w, 6m
+// or 1y, or a YYYY-MM-DD date
+func parseFilterDate(s string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch s {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	if n, err := strconv.Atoi(s[:len(s)-1]); err == nil && n >= 0 {
+		switch s[len(s)-1] {
+		case 'h':
+			return now.Add(-time.Duration(n) * time.Hour), nil
+		case 'd':
+			return now.AddDate(0, 0, -n), nil
+		case 'w':
+			return now.AddDate(0, 0, -7*n), nil
+		case 'm':
+			return now.AddDate(0, -n, 0), nil
+		case 'y':
+			return now.AddDate(-n, 0, 0), nil
+		}
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", s, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unknown date %q (use 7d, 2w, yesterday or 2006-01-02)", s)
+	}
+	return t, nil
+}
+
+func commitFilterCmd(path, rev, filter string, limit int) tea.Cmd {
+	return func() tea.Msg {
+		q, err := parseCommitFilter(filter, time.Now())
+		if err != nil {
+			return errMsg(err)
+		}
+		q.Limit = limit + 1 // One past the limit tells whether the list is complete
+
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		hash, err := git.ResolveRevision(r, rev)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		commits, err := git.QueryCommits(r, hash, q)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		more := len(commits) > limit
+		if more {
+			commits = commits[:limit]
+		}
+
+		return commitFilterMsg{Filter: filter, Commits: commits, More: more}
+	}
+}
+
+// openCommitFilter asks for a filter for the commits panel; an empty filter clears it
+func (m Model) openCommitFilter() Model {
+	m.setFocus(FocusCommits)
+	m.Prompt = NewPromptModel("Filter commits:", m.CommitsModel.Filter, func(filter string) tea.Cmd {
+		if filter == "" {
+			return func() tea.Msg { return commitFilterMsg{} }
+		}
+		return commitFilterCmd(m.RepoInfo.Path, m.InspectedBranch, filter, commitCount(m.Config))
+	})
+	m.Prompt.Input.Placeholder = commitFilterHelp
+	return m
+}
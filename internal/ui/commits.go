@@ -4,42 +4,176 @@ import (
 	"fmt"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
 	"github.com/sh9336/gitdash/internal/git"
 )
 
 type CommitsModel struct {
-	Commits []git.Commit
+	Commits   []git.Commit
+	Graph     []git.Commit // The same window in graph order, with decorations
+	ShowGraph bool
+	Filter    string       // Active filter prompt text, empty when unfiltered
+	Filtered  []git.Commit // Matches of Filter
+	More      bool         // Filtered stopped at the limit, older commits may match too
+	Selected  int          // Index into the visible list
+	Highlight string       // Hash of the commit made from the dashboard, marked in the list
+	Active    bool         // Whether this panel is currently active/focused
 }
 
-func NewCommitsModel(commits []git.Commit) CommitsModel {
+func NewCommitsModel(commits, graph []git.Commit) CommitsModel {
 	return CommitsModel{
 		Commits: commits,
+		Graph:   graph,
 	}
 }
 
+// Visible is the list currently shown: filter matches, the graph or the plain log
+func (m CommitsModel) Visible() []git.Commit {
+	switch {
+	case m.Filter != "":
+		return m.Filtered
+	case m.ShowGraph:
+		return m.Graph
+	}
+	return m.Commits
+}
+
+// graphMode reports whether the graph is drawn; filtered results are a flat list
+func (m CommitsModel) graphMode() bool {
+	return m.ShowGraph && m.Filter == ""
+}
+
+// SetFilter shows the matches of a filter, or the full log again for an empty one
+func (m *CommitsModel) SetFilter(filter string, matches []git.Commit, more bool) {
+	m.Filter = filter
+	m.Filtered = matches
+	m.More = more
+	m.Selected = 0
+}
+
+// ToggleGraph switches between list and graph mode, keeping the selected commit
+func (m *CommitsModel) ToggleGraph() {
+	selected, ok := m.SelectedCommit()
+	m.ShowGraph = !m.ShowGraph
+	m.Selected = 0
+	if ok {
+		m.selectCommit(selected.Hash)
+	}
+}
+
+func (m *CommitsModel) selectCommit(hash string) {
+	for i, c := range m.Visible() {
+		if c.Hash == hash {
+			m.Selected = i
+			return
+		}
+	}
+}
+
+func (m *CommitsModel) Next() {
+	if m.Selected < len(m.Visible())-1 {
+		m.Selected++
+	}
+}
+
+func (m *CommitsModel) Previous() {
+	if m.Selected > 0 {
+		m.Selected--
+	}
+}
+
+// SelectedCommit returns the highlighted commit, if any
+func (m CommitsModel) SelectedCommit() (git.Commit, bool) {
+	visible := m.Visible()
+	if m.Selected < 0 || m.Selected >= len(visible) {
+		return git.Commit{}, false
+	}
+	return visible[m.Selected], true
+}
+
+// handleCommitsKey runs the Commits panel bindings; ok is false for keys it does not own
+func (m Model) handleCommitsKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.CommitsModel.Previous()
+		return m, nil, true
+	case "down", "j":
+		m.CommitsModel.Next()
+		return m, nil, true
+	case "enter":
+		c, ok := m.CommitsModel.SelectedCommit()
+		if !ok {
+			return m, nil, true
+		}
+		m.StatusMessage = fmt.Sprintf("Loading %s...", c.Hash[:7])
+		return m, commitDetailCmd(m.RepoInfo.Path, c.Hash), true
+	case "G":
+		m.CommitsModel.ToggleGraph()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
 func (m CommitsModel) View(width int) string {
 	var s strings.Builder
 
 	// Header
-	s.WriteString(StyleHeader.Render("Recent Commits"))
+	if m.Active {
+		s.WriteString(StyleSelected.Copy().Bold(true).Render("★ Recent Commits"))
+	} else {
+		s.WriteString(StyleHeader.Render("Recent Commits"))
+	}
+	if m.Filter != "" {
+		count := fmt.Sprint(len(m.Filtered))
+		if m.More {
+			count = "first " + count + ", more further back"
+		}
+		s.WriteString(StyleDim.Render(fmt.Sprintf("  filter: %s (%s)", m.Filter, count)))
+	} else if m.ShowGraph {
+		s.WriteString(StyleDim.Render("  graph"))
+	}
 	s.WriteString("\n")
 	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(strings.Repeat("─", width)))
 	s.WriteString("\n")
 
-	if len(m.Commits) == 0 {
-		s.WriteString(StyleDim.Render("   No commits found"))
-		return StylePanel.Copy().Width(width).Render(s.String())
+	commits := m.Visible()
+	if len(commits) == 0 {
+		if m.Filter != "" {
+			s.WriteString(StyleDim.Render("   No commits match, press / to change the filter"))
+		} else {
+			s.WriteString(StyleDim.Render("   No commits found"))
+		}
+		return m.panelStyle(width).Render(s.String())
+	}
+
+	var graphRows, graphBelow []string
+	if m.graphMode() {
+		graphRows, graphBelow = layoutGraph(commits)
 	}
+	graphStyle := lipgloss.NewStyle().Foreground(ColorInfo)
 
-	for _, c := range m.Commits {
+	for i, c := range commits {
 		// Truncate hash
 		hash := c.Hash[:7]
 
+		graph, graphNext, decorations := "", "", ""
+		if m.graphMode() {
+			graph = graphStyle.Render(graphRows[i]) + " "
+			graphNext = graphStyle.Render(graphBelow[i]) + " "
+			decorations = renderDecorations(c.Refs)
+		}
+
+		hashStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+		if c.Hash == m.Highlight {
+			hashStyle = lipgloss.NewStyle().Foreground(ColorSuccess).Bold(true)
+			decorations += lipgloss.NewStyle().Foreground(ColorSuccess).Render("✚ new ")
+		}
+
 		// Message truncation
 		msg := strings.Split(c.Message, "\n")[0]
-		maxLen := width - 14 // 14 gives space for padding check
+		maxLen := width - 14 - lipgloss.Width(graph) - lipgloss.Width(decorations) // 14 gives space for padding check
 		if maxLen < 10 {
 			maxLen = 10
 		}
@@ -51,12 +185,23 @@ func (m CommitsModel) View(width int) string {
 
 		// Layout: Hash Msg Author Time
 		// Add padding " "
-		line1 := fmt.Sprintf(" %s %s",
-			lipgloss.NewStyle().Foreground(lipgloss.Color("205")).Render(hash),
-			StyleNormal.Render(msg),
+		cursor := " "
+		msgText := StyleNormal.Render(msg)
+		if m.Active && i == m.Selected {
+			cursor = "▶"
+			msgText = StyleSelected.Copy().Underline(true).Render(msg)
+		}
+
+		line1 := fmt.Sprintf("%s%s%s %s%s",
+			cursor,
+			graph,
+			hashStyle.Render(hash),
+			decorations,
+			msgText,
 		)
 
-		line2 := fmt.Sprintf("         %s, %s",
+		line2 := fmt.Sprintf(" %s        %s, %s",
+			graphNext,
 			StyleDim.Render(c.Author),
 			StyleDim.Render(timeStr),
 		)
@@ -64,5 +209,32 @@ func (m CommitsModel) View(width int) string {
 		s.WriteString(line1 + "\n" + line2 + "\n")
 	}
 
-	return StylePanel.Copy().Width(width).Render(s.String())
+	return m.panelStyle(width).Render(s.String())
+}
+
+// renderDecorations renders ref names like `git log --decorate`: (HEAD -> main, origin/main, tag: v1)
+func renderDecorations(refs []git.Decoration) string {
+	if len(refs) == 0 {
+		return ""
+	}
+
+	styles := map[git.DecorationKind]lipgloss.Style{
+		git.DecorationHead:   StyleSelected,
+		git.DecorationBranch: lipgloss.NewStyle().Foreground(ColorSuccess),
+		git.DecorationRemote: lipgloss.NewStyle().Foreground(ColorError),
+		git.DecorationTag:    lipgloss.NewStyle().Foreground(ColorWarning),
+	}
+	parts := make([]string, len(refs))
+	for i, ref := range refs {
+		parts[i] = styles[ref.Kind].Render(ref.Name)
+	}
+	return StyleDim.Render("(") + strings.Join(parts, StyleDim.Render(", ")) + StyleDim.Render(") ")
+}
+
+func (m CommitsModel) panelStyle(width int) lipgloss.Style {
+	style := StylePanel.Copy().Width(width)
+	if m.Active {
+		style = style.BorderForeground(ColorPrimary)
+	}
+	return style
 }
@@ -0,0 +1,206 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// CompareModel is the full-screen comparison of two branches: the commits
+// unique to each side and what B changed since the merge-base
+type CompareModel struct {
+	A, B       string
+	Comparison *git.Comparison
+	Diff       []git.FileDiff
+	Selected   int // Index into OnlyA followed by OnlyB
+	top        int // First line on screen
+	width      int
+	height     int
+}
+
+type compareMsg struct {
+	A, B       string
+	Comparison *git.Comparison
+}
+
+func compareCmd(repoPath, a, b string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		ha, err := git.ResolveRevision(r, a)
+		if err != nil {
+			return errMsg(err)
+		}
+		hb, err := git.ResolveRevision(r, b)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		cmp, err := git.CompareCommits(r, ha, hb)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return compareMsg{A: a, B: b, Comparison: cmp}
+	}
+}
+
+func NewCompareModel(a, b string, cmp *git.Comparison, width, height int) CompareModel {
+	m := CompareModel{
+		A:          a,
+		B:          b,
+		Comparison: cmp,
+		width:      width,
+		height:     height,
+	}
+	if cmp != nil {
+		m.Diff = git.FileDiffs(cmp.Patch, git.DefaultContext)
+	}
+	return m
+}
+
+func (m *CompareModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m CompareModel) rows() int {
+	return max(m.height-6, 1)
+}
+
+// commits is every listed commit in display order
+func (m CompareModel) commits() []git.Commit {
+	if m.Comparison == nil {
+		return nil
+	}
+	return append(append([]git.Commit{}, m.Comparison.OnlyA...), m.Comparison.OnlyB...)
+}
+
+// SelectedCommit returns the highlighted commit, if any
+func (m CompareModel) SelectedCommit() (git.Commit, bool) {
+	commits := m.commits()
+	if m.Selected < 0 || m.Selected >= len(commits) {
+		return git.Commit{}, false
+	}
+	return commits[m.Selected], true
+}
+
+// Update moves the selection with the arrows; the page keys scroll, so a
+// long file list stays reachable
+func (m CompareModel) Update(msg tea.KeyMsg) CompareModel {
+	if m.Comparison == nil {
+		return m
+	}
+	last := len(m.commits()) - 1
+	lines, _ := m.lines()
+	maxTop := max(len(lines)-m.rows(), 0)
+
+	switch msg.String() {
+	case "up", "k":
+		m.Selected = max(m.Selected-1, 0)
+	case "down", "j":
+		m.Selected = max(min(m.Selected+1, last), 0)
+	case "pgup", "b":
+		m.top = max(m.top-m.rows(), 0)
+		return m
+	case "pgdown", "f", " ":
+		m.top = min(m.top+m.rows(), maxTop)
+		return m
+	case "home", "g":
+		m.Selected, m.top = 0, 0
+		return m
+	case "end", "G":
+		m.Selected, m.top = max(last, 0), maxTop
+		return m
+	default:
+		return m
+	}
+
+	// Keep the selected commit on screen
+	_, selected := m.lines()
+	if selected < m.top {
+		m.top = selected
+	}
+	if selected >= m.top+m.rows() {
+		m.top = selected - m.rows() + 1
+	}
+	return m
+}
+
+// lines renders the whole comparison and the line of the selected commit
+func (m CompareModel) lines() ([]string, int) {
+	c := m.Comparison
+	var lines []string
+	selectedLine := 0
+
+	section := func(title string, commits []git.Commit, offset int) {
+		lines = append(lines, StyleHeader.Render(fmt.Sprintf("%s (%d)", title, len(commits))))
+		if len(commits) == 0 {
+			lines = append(lines, StyleDim.Render("   nothing"))
+		}
+		for i, commit := range commits {
+			cursor := "  "
+			subject := StyleNormal.Render(strings.Split(commit.Message, "\n")[0])
+			if offset+i == m.Selected {
+				cursor = "▶ "
+				subject = StyleSelected.Copy().Underline(true).Render(strings.Split(commit.Message, "\n")[0])
+				selectedLine = len(lines)
+			}
+			lines = append(lines, fmt.Sprintf(" %s%s %s %s", cursor,
+				StyleSelected.Render(commit.Hash[:7]),
+				subject,
+				StyleDim.Render(fmt.Sprintf("(%s, %s)", commit.Author, humanize.Time(commit.When)))))
+		}
+		lines = append(lines, "")
+	}
+	section("Only in "+inspectLabel(m.A), c.OnlyA, 0)
+	section("Only in "+inspectLabel(m.B), c.OnlyB, len(c.OnlyA))
+
+	title := fmt.Sprintf("Changes on %s since the merge-base", inspectLabel(m.B))
+	if c.MergeBase == "" {
+		title = fmt.Sprintf("Changes from %s to %s", inspectLabel(m.A), inspectLabel(m.B))
+	}
+	lines = append(lines, StyleHeader.Render(title))
+	lines = append(lines, strings.Split(strings.TrimRight(renderFileStats(c.Files), "\n"), "\n")...)
+
+	return lines, selectedLine
+}
+
+func (m CompareModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(StyleTitle.Render(fmt.Sprintf("Compare %s ↔ %s", inspectLabel(m.A), inspectLabel(m.B))))
+	s.WriteString("\n")
+	if m.Comparison == nil {
+		return s.String()
+	}
+
+	base := StyleDim.Render("none, the histories are unrelated")
+	if m.Comparison.MergeBase != "" {
+		base = StyleSelected.Render(m.Comparison.MergeBase[:7])
+	}
+	s.WriteString(fmt.Sprintf(" merge-base %s %s\n\n", base,
+		StyleDim.Render(fmt.Sprintf("• %s ↑%d • %s ↑%d", inspectLabel(m.A), len(m.Comparison.OnlyA), inspectLabel(m.B), len(m.Comparison.OnlyB)))))
+
+	lines, _ := m.lines()
+	rows := m.rows()
+	top := min(m.top, max(len(lines)-rows, 0))
+	end := min(top+rows, len(lines))
+	for _, line := range lines[top:end] {
+		s.WriteString(lipgloss.NewStyle().MaxWidth(m.width).Render(line))
+		s.WriteString("\n")
+	}
+	for i := end - top; i < rows; i++ {
+		s.WriteString("\n")
+	}
+
+	s.WriteString(StyleDim.Render("\n '↑/↓' select, 'PgUp/PgDn' scroll, 'Enter' open commit, 'd' diff since merge-base, 'Esc' back"))
+	return s.String()
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// Subject length guide, as in most commit message conventions
+const (
+	subjectSoftLimit = 50
+	subjectHardLimit = 72
+)
+
+// ComposerModel is the commit screen: the staged files, the author and a
+// message editor. The draft survives closing the screen until it is committed.
+type ComposerModel struct {
+	Editor      textarea.Model
+	Staged      []git.FileStatus
+	Author      string // "Name <email>", empty when user.name/email are unset
+	HeadMessage string // Message of HEAD, loaded into the editor when amending
+	HeadHash    string // Empty on an unborn branch, nothing to amend then
+	Amend       bool
+	Draft       string // Editor content before amend replaced it
+	Error       string
+	width       int
+	height      int
+}
+
+// composerMsg opens the commit screen with fresh repository state
+type composerMsg struct {
+	Status      *git.WorkingDirStatus
+	Author      string
+	HeadMessage string
+	HeadHash    string
+	Pending     string // Message git prepared for a merge, cherry-pick or revert
+}
+
+// commitDoneMsg reports a commit; a failure keeps the screen open
+type commitDoneMsg struct {
+	Hash  string
+	Amend bool
+	Err   error
+}
+
+func composerCmd(repoPath string, renames git.RenameOptions) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		status, err := git.GetWorkingDirStatus(r, renames)
+		if err != nil {
+			return errMsg(err)
+		}
+		msg := composerMsg{Status: status, Pending: git.PendingMessage(r)}
+		if sig, ok := git.Identity(r); ok {
+			msg.Author = fmt.Sprintf("%s <%s>", sig.Name, sig.Email)
+		}
+		if head, err := r.Head(); err == nil {
+			msg.HeadHash = head.Hash().String()
+			if msg.HeadMessage, err = git.HeadMessage(r); err != nil {
+				return errMsg(err)
+			}
+		}
+
+		return msg
+	}
+}
+
+func createCommitCmd(repoPath, message string, amend bool) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		hash, err := git.CreateCommit(r, message, amend)
+		if err != nil {
+			return commitDoneMsg{Err: err}
+		}
+
+		return commitDoneMsg{Hash: hash.String(), Amend: amend}
+	}
+}
+
+func NewComposerModel(width, height int) ComposerModel {
+	editor := textarea.New()
+	editor.ShowLineNumbers = false
+	editor.Prompt = "│ "
+	editor.CharLimit = 0
+	editor.Placeholder = "Subject line, then a blank line and the body"
+	editor.Focus()
+
+	m := ComposerModel{Editor: editor}
+	m.SetSize(width, height)
+	return m
+}
+
+// SetState takes the staged files and HEAD from a fresh load, keeping the draft
+func (m *ComposerModel) SetState(msg composerMsg) {
+	m.Staged = nil
+	for _, f := range msg.Status.Files {
+		if f.Staged {
+			m.Staged = append(m.Staged, f)
+		}
+	}
+	m.Author = msg.Author
+	m.HeadMessage = msg.HeadMessage
+	m.HeadHash = msg.HeadHash
+	m.Error = ""
+	if m.HeadHash == "" && m.Amend {
+		m.ToggleAmend()
+	}
+	if !m.Amend && msg.Pending != "" && strings.TrimSpace(m.Editor.Value()) == "" {
+		m.Editor.SetValue(msg.Pending)
+	}
+	m.SetSize(m.width, m.height)
+}
+
+// stagedShown is how many staged files are listed before "and N more"
+const stagedShown = 8
+
+func (m *ComposerModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+
+	// Title, author, staged heading and list, ruler, guide and footer
+	used := 9 + min(len(m.Staged), stagedShown+1)
+	m.Editor.SetWidth(max(width-2, 20))
+	m.Editor.SetHeight(max(height-used, 3))
+}
+
+// ToggleAmend switches amending on and off. Amending loads the HEAD message
+// into an empty editor; switching back restores what was typed before.
+func (m *ComposerModel) ToggleAmend() {
+	if !m.Amend && m.HeadHash == "" {
+		m.Error = "Nothing to amend, the branch has no commits yet"
+		return
+	}
+	m.Amend = !m.Amend
+	m.Error = ""
+	if m.Amend {
+		m.Draft = m.Editor.Value()
+		if strings.TrimSpace(m.Draft) == "" {
+			m.Editor.SetValue(strings.TrimRight(m.HeadMessage, "\n"))
+		}
+		return
+	}
+	if strings.TrimSpace(m.Draft) == "" && m.Editor.Value() == strings.TrimRight(m.HeadMessage, "\n") {
+		m.Editor.SetValue(m.Draft)
+	}
+	m.Draft = ""
+}
+
+func (m ComposerModel) Update(msg tea.Msg) (ComposerModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Editor, cmd = m.Editor.Update(msg)
+	return m, cmd
+}
+
+func (m ComposerModel) View() string {
+	var s strings.Builder
+
+	title := "Commit"
+	if m.Amend {
+		title = "Amend " + m.HeadHash[:7]
+	}
+	s.WriteString(StyleTitle.Render(title))
+	s.WriteString("\n")
+	if m.Author == "" {
+		s.WriteString(lipgloss.NewStyle().Foreground(ColorError).Render(" user.name and user.email are not set, git will refuse to commit"))
+	} else {
+		s.WriteString(StyleDim.Render(" as ") + StyleNormal.Render(m.Author))
+	}
+	s.WriteString("\n\n")
+
+	switch {
+	case len(m.Staged) == 0 && m.Amend:
+		s.WriteString(StyleDim.Render(" Nothing staged, only the message changes") + "\n")
+	case len(m.Staged) == 0:
+		s.WriteString(lipgloss.NewStyle().Foreground(ColorWarning).Render(" Nothing staged, stage files in the Working Directory panel first") + "\n")
+	default:
+		s.WriteString(StyleHeader.Render(fmt.Sprintf(" Staged (%d)", len(m.Staged))) + "\n")
+		shown := min(len(m.Staged), stagedShown)
+		for _, f := range m.Staged[:shown] {
+			name := f.Path
+			if f.OldPath != "" {
+				name = renameLabel(f, name)
+			}
+			s.WriteString(fmt.Sprintf("   %s %s\n", lipgloss.NewStyle().Foreground(ColorSuccess).Render(f.Status), name))
+		}
+		if len(m.Staged) > shown {
+			s.WriteString(StyleDim.Render(fmt.Sprintf("   ... and %d more", len(m.Staged)-shown)) + "\n")
+		}
+	}
+	s.WriteString("\n")
+
+	s.WriteString(subjectRuler() + "\n")
+	s.WriteString(m.Editor.View())
+	s.WriteString("\n")
+	s.WriteString(m.messageGuide())
+
+	footer := "\n 'Ctrl+S' commit, 'Ctrl+T' amend, 'Esc' back (the draft is kept)"
+	if m.Error != "" {
+		footer = "\n " + lipgloss.NewStyle().Foreground(ColorError).Render("Error: "+m.Error)
+	}
+	s.WriteString(StyleDim.Render(footer))
+	return s.String()
+}
+
+// subjectRuler marks the soft and hard subject limits above the editor
+func subjectRuler() string {
+	ruler := strings.Repeat("·", subjectSoftLimit-1) + "┊" + strings.Repeat("·", subjectHardLimit-subjectSoftLimit-1) + "┊"
+	return StyleDim.Render("  " + ruler)
+}
+
+// messageGuide reports the subject length and a missing blank second line
+func (m ComposerModel) messageGuide() string {
+	lines := strings.Split(m.Editor.Value(), "\n")
+	n := len([]rune(strings.TrimRight(lines[0], " ")))
+
+	style := lipgloss.NewStyle().Foreground(ColorSuccess)
+	switch {
+	case n > subjectHardLimit:
+		style = lipgloss.NewStyle().Foreground(ColorError)
+	case n > subjectSoftLimit:
+		style = lipgloss.NewStyle().Foreground(ColorWarning)
+	}
+	guide := style.Render(fmt.Sprintf(" subject %d/%d", n, subjectSoftLimit))
+	if len(lines) > 1 && strings.TrimSpace(lines[1]) != "" {
+		guide += lipgloss.NewStyle().Foreground(ColorWarning).Render(" • leave the second line blank")
+	}
+	return guide
+}
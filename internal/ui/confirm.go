@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ConfirmModel is a yes/no question asked in the footer before a destructive
+// action runs. The action only starts when the user answers 'y'.
+type ConfirmModel struct {
+	Question  string
+	Details   []string // What the action would lose; asks in a dialog instead of the footer
+	OnConfirm tea.Cmd
+}
+
+func NewConfirmModel(question string, onConfirm tea.Cmd) ConfirmModel {
+	return ConfirmModel{
+		Question:  question,
+		OnConfirm: onConfirm,
+	}
+}
+
+func (c ConfirmModel) Active() bool {
+	return c.OnConfirm != nil
+}
+
+func (c ConfirmModel) View() string {
+	return StyleSelected.Render(c.Question) + StyleDim.Render(" (y/N)")
+}
+
+// confirmDialog shows a question with details centered over the screen
+func (m Model) confirmDialog() string {
+	var s strings.Builder
+	s.WriteString(lipgloss.NewStyle().Bold(true).Foreground(ColorWarning).Render(m.Confirm.Question))
+	s.WriteString("\n\n")
+
+	const shown = 12
+	for _, line := range m.Confirm.Details[:min(len(m.Confirm.Details), shown)] {
+		s.WriteString("  " + line + "\n")
+	}
+	if len(m.Confirm.Details) > shown {
+		s.WriteString(StyleDim.Render(fmt.Sprintf("  ... and %d more", len(m.Confirm.Details)-shown)) + "\n")
+	}
+
+	key := lipgloss.NewStyle().Foreground(ColorInfo).Bold(true)
+	s.WriteString("\n" + key.Render("y") + " yes   " + key.Render("any other key") + " cancel")
+
+	box := lipgloss.NewStyle().
+		Width(min(80, max(m.Width-4, 20))).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(ColorWarning).
+		Padding(1, 2).
+		Render(s.String())
+
+	return lipgloss.Place(m.Width, m.Height, lipgloss.Center, lipgloss.Center, box)
+}
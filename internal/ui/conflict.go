@@ -0,0 +1,195 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// Tabs of the conflict view; the first four index Files.Versions
+const (
+	conflictBase = iota
+	conflictOurs
+	conflictTheirs
+	conflictWorktree
+	conflictChanges
+)
+
+// ConflictModel shows one conflicted file: the base, ours and theirs
+// versions from the index, the worktree file with its markers, and what
+// each side changed
+type ConflictModel struct {
+	Files    *git.ConflictFiles
+	Tab      int
+	Viewport viewport.Model
+}
+
+type conflictMsg struct {
+	Files *git.ConflictFiles
+}
+
+func conflictCmd(repoPath, path string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		files, err := git.GetConflictFiles(r, path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return conflictMsg{Files: files}
+	}
+}
+
+func NewConflictModel(files *git.ConflictFiles, width, height int) ConflictModel {
+	m := ConflictModel{
+		Files:    files,
+		Tab:      conflictOurs,
+		Viewport: viewport.New(0, 0),
+	}
+	m.Viewport.SetHorizontalStep(8)
+	m.SetSize(width, height)
+	return m
+}
+
+func (m *ConflictModel) SetSize(width, height int) {
+	m.Viewport.Width = width
+	m.Viewport.Height = max(height-5, 1)
+	m.render()
+}
+
+func (m *ConflictModel) render() {
+	if m.Files == nil {
+		return
+	}
+	if m.Tab == conflictChanges {
+		m.Viewport.SetContent(m.renderChanges())
+	} else {
+		m.Viewport.SetContent(m.renderVersion(m.Files.Versions[m.Tab]))
+	}
+	m.Viewport.GotoTop()
+}
+
+func (m ConflictModel) Update(msg tea.KeyMsg) (ConflictModel, tea.Cmd) {
+	tab := m.Tab
+	switch msg.String() {
+	case "b":
+		tab = conflictBase
+	case "o":
+		tab = conflictOurs
+	case "t":
+		tab = conflictTheirs
+	case "w":
+		tab = conflictWorktree
+	case "d":
+		tab = conflictChanges
+	case "tab":
+		tab = (m.Tab + 1) % (conflictChanges + 1)
+	case "shift+tab":
+		tab = (m.Tab + conflictChanges) % (conflictChanges + 1)
+	default:
+		var cmd tea.Cmd
+		m.Viewport, cmd = m.Viewport.Update(msg)
+		return m, cmd
+	}
+	if tab != m.Tab {
+		m.Tab = tab
+		m.render()
+	}
+	return m, nil
+}
+
+// renderVersion numbers the lines of one version, marking conflict markers
+func (m ConflictModel) renderVersion(v git.ConflictVersion) string {
+	switch {
+	case !v.Exists && v.Label == "base":
+		return StyleDim.Render("   No common base, the file was added on both sides")
+	case !v.Exists && v.Label == "worktree":
+		return StyleDim.Render("   The file is not in the working directory")
+	case !v.Exists:
+		return StyleDim.Render(fmt.Sprintf("   Deleted on the %s side", v.Label))
+	case v.Binary:
+		return StyleDim.Render("   Binary file, contents not shown")
+	}
+
+	marker := lipgloss.NewStyle().Foreground(ColorWarning).Bold(true)
+	var s strings.Builder
+	for i, line := range strings.Split(strings.TrimSuffix(v.Content, "\n"), "\n") {
+		text := expandTabs(line)
+		if isConflictMarker(line) {
+			text = marker.Render(text)
+		}
+		s.WriteString(StyleDim.Render(fmt.Sprintf("%5d │ ", i+1)) + text + "\n")
+	}
+	return s.String()
+}
+
+// isConflictMarker matches the lines git writes around conflicting hunks
+func isConflictMarker(line string) bool {
+	for _, m := range []string{"<<<<<<<", "=======", ">>>>>>>", "|||||||"} {
+		if strings.HasPrefix(line, m) && (len(line) == len(m) || line[len(m)] == ' ') {
+			return true
+		}
+	}
+	return false
+}
+
+// renderChanges shows what ours and theirs each changed relative to the base
+func (m ConflictModel) renderChanges() string {
+	var s strings.Builder
+	for i, title := range []string{"Ours, compared with the base", "Theirs, compared with the base"} {
+		if i > 0 {
+			s.WriteString("\n")
+		}
+		s.WriteString(StyleHeader.Render(title) + "\n")
+
+		f := m.Files.Diffs[i]
+		switch {
+		case f.Binary:
+			s.WriteString(StyleDim.Render("   Binary file, contents not shown") + "\n")
+			continue
+		case f.Status() == "D":
+			s.WriteString(StyleDim.Render("   Deleted the file") + "\n")
+			continue
+		case len(f.Hunks) == 0:
+			s.WriteString(StyleDim.Render("   No changes") + "\n")
+			continue
+		}
+		for _, h := range f.Hunks {
+			s.WriteString(StyleDiffHunk.Render(h.Header()) + "\n")
+			for _, line := range renderHunkLines(h, true) {
+				s.WriteString(line + "\n")
+			}
+		}
+	}
+	return s.String()
+}
+
+func (m ConflictModel) View() string {
+	var s strings.Builder
+
+	f := m.Files
+	s.WriteString(StyleTitle.Render(fmt.Sprintf("Conflict in %s: %s (%s)", f.Path, f.Code(), f.Type())))
+	s.WriteString("\n")
+
+	var tabs []string
+	for i, label := range []string{"b base", "o ours", "t theirs", "w worktree", "d changes"} {
+		if i == m.Tab {
+			tabs = append(tabs, StyleSelected.Copy().Underline(true).Render(label))
+		} else {
+			tabs = append(tabs, StyleDim.Render(label))
+		}
+	}
+	s.WriteString(" " + strings.Join(tabs, "   ") + "\n\n")
+
+	s.WriteString(m.Viewport.View())
+	s.WriteString(StyleDim.Render(fmt.Sprintf("\n 'b/o/t/w/d' or 'Tab' switch version, '↑/↓' scroll, 'Esc' back • stage the file to mark it resolved • %3.f%%", m.Viewport.ScrollPercent()*100)))
+	return s.String()
+}
@@ -18,6 +18,29 @@ type FocusArea int
 const (
 	FocusNone FocusArea = iota
 	FocusBranches
+	FocusTags
+	FocusCommits
+	FocusStash
+	FocusWorkDir
+)
+
+// Screen selects what the dashboard renders: the panels or a full-screen view
+type Screen int
+
+const (
+	ScreenDashboard Screen = iota
+	ScreenStashDetail
+	ScreenCommitDetail
+	ScreenDiff
+	ScreenFileHistory
+	ScreenFileBrowser
+	ScreenBlame
+	ScreenCompare
+	ScreenForecast
+	ScreenCleanup
+	ScreenStaging
+	ScreenComposer
+	ScreenConflict
 )
 
 type checkoutTickMsg struct{}
@@ -25,13 +48,20 @@ type checkoutTickMsg struct{}
 type checkoutDoneMsg struct {
 	Path   string
 	Target string
+	Note   string // Reported once the dashboard has caught up, e.g. a kept stash
 }
 
 type errMsg error
 
+// inspectMsg switches inspection to a revision that is known to resolve
+type inspectMsg struct {
+	Rev string
+}
+
 type refreshMsg struct {
 	RepoInfo      *git.RepoInfo
 	BranchesModel BranchesModel
+	TagsModel     TagsModel
 	CommitsModel  CommitsModel
 	WorkDirModel  WorkDirModel
 	StashModel    StashModel
@@ -42,10 +72,24 @@ type Model struct {
 	Config          *config.Config
 	RepoInfo        *git.RepoInfo
 	BranchesModel   BranchesModel
+	TagsModel       TagsModel
 	CommitsModel    CommitsModel
 	WorkDirModel    WorkDirModel
 	StashModel      StashModel
 	StatsModel      StatsModel
+	StashDetail     StashDetailModel
+	CommitDetail    CommitDetailModel
+	Diff            DiffViewModel
+	FileHistory     FileHistoryModel
+	FileBrowser     FileBrowserModel
+	Blame           BlameModel
+	Compare         CompareModel
+	Forecast        ForecastModel
+	Cleanup         CleanupModel
+	Staging         StagingModel
+	Composer        ComposerModel
+	Conflict        ConflictModel
+	Back            []Screen // Screens to return to when the current one closes, most recent last
 	Viewport        viewport.Model
 	Quitting        bool
 	Width           int
@@ -53,11 +97,17 @@ type Model struct {
 	Loading         bool
 	ShowHelp        bool
 	Focus           FocusArea
+	Screen          Screen
 	InspectedBranch string // Branch currently being viewed/inspected
 	StatusMessage   string
-	Spinner         int    // For checkout animation
-	CheckingOut     string // Name of branch being checked out
+	Confirm         ConfirmModel  // Pending yes/no question, if any
+	Prompt          PromptModel   // Pending text input, if any
+	Checkout        CheckoutModal // Blocked checkout waiting for a decision, if any
+	CheckoutNote    string        // Added to the status once the switch is refreshed
+	Spinner         int           // For checkout animation
+	CheckingOut     string        // Name of branch being checked out
 	RefreshTries    int
+	SelectCommit    string // Commit to select in the Commits panel once the next refresh lands
 }
 
 func NewModel(info *git.RepoInfo, cfg *config.Config) Model {
@@ -73,23 +123,30 @@ func NewModel(info *git.RepoInfo, cfg *config.Config) Model {
 		Spinner:         0,
 		CheckingOut:     "",
 		RefreshTries:    0,
+		Composer:        NewComposerModel(0, 0),
 	}
 
-	// Use config for commit count
-	commitCount := 10
-	if cfg != nil && cfg.Commits.ShowCount > 0 {
-		commitCount = cfg.Commits.ShowCount
-	}
+	commitCount := commitCount(cfg)
 
 	branches, _ := git.GetBranches(info.Repo)
-	commits, _ := git.GetRecentCommits(info.Repo, m.InspectedBranch, commitCount)
-	status, _ := git.GetWorkingDirStatus(info.Repo)
+	remotes, _ := git.GetRemoteBranches(info.Repo)
+	tags, _ := git.GetTags(info.Repo, tagSort(cfg))
+	// Unresolvable revisions (e.g. an empty repository) resolve to the zero hash and show nothing
+	if info.Detached {
+		m.InspectedBranch = info.HeadHash
+	}
+	hash, _ := git.ResolveRevision(info.Repo, m.InspectedBranch)
+	commits, _ := git.GetRecentCommits(info.Repo, hash, commitCount)
+	graph, _ := git.GetCommitGraph(info.Repo, hash, commitCount)
+	status, _ := git.GetWorkingDirStatus(info.Repo, renameOptions(cfg))
 	stashes, _ := git.GetStashList(info.Repo)
-	projectStats, _ := stats.CalculateStats(info.Repo, m.InspectedBranch)
+	projectStats, _ := stats.CalculateStats(info.Repo, hash)
 
-	m.BranchesModel = NewBranchesModel(branches)
+	m.BranchesModel = NewBranchesModel(branches, remotes)
 	m.BranchesModel.Active = true // Since we default focus
-	m.CommitsModel = NewCommitsModel(commits)
+	m.TagsModel = NewTagsModel(tags, tagSort(cfg))
+	m.CommitsModel = NewCommitsModel(commits, graph)
+	m.CommitsModel.ShowGraph = cfg != nil && cfg.Commits.Graph
 	m.WorkDirModel = NewWorkDirModel(status)
 	m.StashModel = NewStashModel(stashes)
 	m.StatsModel = NewStatsModel(projectStats)
@@ -109,26 +166,28 @@ func refreshData(info *git.RepoInfo, cfg *config.Config, branchName string, full
 			return errMsg(err)
 		}
 
-		commitCount := 10
-		if cfg != nil && cfg.Commits.ShowCount > 0 {
-			commitCount = cfg.Commits.ShowCount
-		}
+		commitCount := commitCount(cfg)
 
 		branches, _ := git.GetBranches(newInfo.Repo)
-		commits, _ := git.GetRecentCommits(newInfo.Repo, branchName, commitCount)
-		status, _ := git.GetWorkingDirStatus(newInfo.Repo)
+		remotes, _ := git.GetRemoteBranches(newInfo.Repo)
+		tags, _ := git.GetTags(newInfo.Repo, tagSort(cfg))
+		hash, _ := git.ResolveRevision(newInfo.Repo, branchName)
+		commits, _ := git.GetRecentCommits(newInfo.Repo, hash, commitCount)
+		graph, _ := git.GetCommitGraph(newInfo.Repo, hash, commitCount)
+		status, _ := git.GetWorkingDirStatus(newInfo.Repo, renameOptions(cfg))
 		stashes, _ := git.GetStashList(newInfo.Repo)
 
 		msg := refreshMsg{
 			RepoInfo:      newInfo,
-			BranchesModel: NewBranchesModel(branches),
-			CommitsModel:  NewCommitsModel(commits),
+			BranchesModel: NewBranchesModel(branches, remotes),
+			TagsModel:     NewTagsModel(tags, tagSort(cfg)),
+			CommitsModel:  NewCommitsModel(commits, graph),
 			WorkDirModel:  NewWorkDirModel(status),
 			StashModel:    NewStashModel(stashes),
 		}
 
 		if fullRefresh {
-			projectStats, _ := stats.CalculateStats(newInfo.Repo, branchName)
+			projectStats, _ := stats.CalculateStats(newInfo.Repo, hash)
 			statsModel := NewStatsModel(projectStats)
 			msg.StatsModel = &statsModel
 		}
@@ -137,6 +196,47 @@ func refreshData(info *git.RepoInfo, cfg *config.Config, branchName string, full
 	}
 }
 
+// inspectRevisionCmd checks that rev names a commit before the dashboard switches to it
+func inspectRevisionCmd(path, rev string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		if _, err := git.ResolveRevision(r, rev); err != nil {
+			return errMsg(err)
+		}
+
+		return inspectMsg{Rev: rev}
+	}
+}
+
+// commitCount is how many commits the commits panel loads
+func commitCount(cfg *config.Config) int {
+	if cfg != nil && cfg.Commits.ShowCount > 0 {
+		return cfg.Commits.ShowCount
+	}
+	return 10
+}
+
+// renameOptions is the configured rename detection for the working
+// directory status, git's default threshold without a config
+func renameOptions(cfg *config.Config) git.RenameOptions {
+	if cfg == nil {
+		return git.RenameOptions{Threshold: git.DefaultRenameThreshold}
+	}
+	return git.RenameOptions{Threshold: cfg.Status.RenameThreshold, Copies: cfg.Status.Copies}
+}
+
+// tagSort is the configured tag order, semver unless set otherwise
+func tagSort(cfg *config.Config) git.TagSort {
+	if cfg == nil {
+		return git.TagSortSemver
+	}
+	return git.ParseTagSort(cfg.Tags.Sort)
+}
+
 func checkoutCmd(path string, branchName string, force bool) tea.Cmd {
 	return func() tea.Msg {
 		r, err := git.OpenRepo(path)
@@ -156,6 +256,34 @@ func checkoutCmd(path string, branchName string, force bool) tea.Cmd {
 	}
 }
 
+// setFocus moves keyboard focus to the given panel
+func (m *Model) setFocus(f FocusArea) {
+	m.Focus = f
+	m.BranchesModel.Active = f == FocusBranches
+	m.TagsModel.Active = f == FocusTags
+	m.CommitsModel.Active = f == FocusCommits
+	m.StashModel.Active = f == FocusStash
+	m.WorkDirModel.Active = f == FocusWorkDir
+}
+
+// nextFocus cycles General -> Branches -> Tags -> Commits -> Stash -> Working Directory -> General
+func (m Model) nextFocus() FocusArea {
+	switch m.Focus {
+	case FocusNone:
+		return FocusBranches
+	case FocusBranches:
+		return FocusTags
+	case FocusTags:
+		return FocusCommits
+	case FocusCommits:
+		return FocusStash
+	case FocusStash:
+		return FocusWorkDir
+	default:
+		return FocusNone
+	}
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
@@ -165,6 +293,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Loading = true
 		m.StatusMessage = fmt.Sprintf("Switched to %s, syncing dashboard...", msg.Target)
 		m.CheckingOut = msg.Target
+		m.CheckoutNote = msg.Note
 		// EXACT SAME logic as pressing 'r' manually
 		return m, refreshData(m.RepoInfo, m.Config, m.InspectedBranch, true)
 
@@ -175,9 +304,216 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg { return checkoutTickMsg{} })
 		}
 
+	case stashOpDoneMsg:
+		m.Loading = true
+		m.StatusMessage = msg.Message
+		return m, refreshData(m.RepoInfo, m.Config, m.InspectedBranch, false)
+
+	case branchOpDoneMsg:
+		m.Loading = true
+		m.StatusMessage = msg.Message
+		if msg.Inspect != "" {
+			m.InspectedBranch = msg.Inspect
+		}
+		return m, refreshData(m.RepoInfo, m.Config, m.InspectedBranch, msg.Inspect != "")
+
+	case branchUnmergedMsg:
+		m.Loading = false
+		m.StatusMessage = ""
+		m.Confirm = m.unmergedConfirm(msg)
+		return m, nil
+
+	case cleanupMsg:
+		if m.Screen != ScreenCleanup {
+			base, staleDays := cleanupSettings(m.Config)
+			m.Cleanup = NewCleanupModel(base, staleDays, m.Width, m.Height)
+			m = m.openScreen(ScreenCleanup)
+			m.StatusMessage = ""
+		}
+		m.Cleanup.SetCandidates(msg.Candidates)
+		return m, nil
+
+	case cleanupDoneMsg:
+		var summary []string
+		if len(msg.Deleted) > 0 {
+			summary = append(summary, fmt.Sprintf("Deleted %d branch(es), 'u' brings them back", len(msg.Deleted)))
+			m.Cleanup.Undo = msg.Deleted
+			for _, d := range msg.Deleted {
+				if d.Name == m.InspectedBranch {
+					m.InspectedBranch = m.RepoInfo.CurrentBranch
+				}
+			}
+		}
+		if len(msg.Restored) > 0 {
+			summary = append(summary, fmt.Sprintf("Restored %d branch(es)", len(msg.Restored)))
+			m.Cleanup.Undo = nil
+		}
+		if msg.Err != nil {
+			summary = append(summary, lipgloss.NewStyle().Foreground(ColorError).Render(fmt.Sprintf("Error: %v", msg.Err)))
+		}
+		m.Cleanup.Summary = strings.Join(summary, " • ")
+		m.StatusMessage = ""
+		return m, tea.Batch(
+			cleanupCmd(m.RepoInfo.Path, m.Cleanup.Base, m.Cleanup.StaleDays),
+			refreshData(m.RepoInfo, m.Config, m.InspectedBranch, true),
+		)
+
+	case workDirMsg:
+		oldFile := m.WorkDirModel.Selected
+		m.WorkDirModel = NewWorkDirModel(msg.Status)
+		m.WorkDirModel.Selected = max(min(oldFile, len(m.WorkDirModel.Files)-1), 0)
+		m.setFocus(m.Focus)
+		m.StatusMessage = msg.Message
+		m.Viewport.SetContent(m.RenderMainContent())
+		return m, nil
+
+	case fileHunksMsg:
+		if msg.Open {
+			side := sideUnstaged
+			if f, ok := m.WorkDirModel.SelectedFile(); ok && f.Staged {
+				side = sideStaged
+			}
+			m.Staging = NewStagingModel(msg.Hunks, side, m.Width, m.Height)
+			m = m.openScreen(ScreenStaging)
+			m.StatusMessage = ""
+			return m, nil
+		}
+		m.Staging.SetHunks(msg.Hunks)
+		m.Staging.Summary = msg.Message
+		oldFile := m.WorkDirModel.Selected
+		m.WorkDirModel = NewWorkDirModel(msg.Status)
+		m.WorkDirModel.Selected = max(min(oldFile, len(m.WorkDirModel.Files)-1), 0)
+		m.setFocus(m.Focus)
+		m.Viewport.SetContent(m.RenderMainContent())
+		return m, nil
+
+	case conflictMsg:
+		m.Conflict = NewConflictModel(msg.Files, m.Width, m.Height)
+		m = m.openScreen(ScreenConflict)
+		m.StatusMessage = ""
+		return m, nil
+
+	case composerMsg:
+		m.Composer.SetState(msg)
+		m = m.openScreen(ScreenComposer)
+		m.StatusMessage = ""
+		return m, nil
+
+	case commitDoneMsg:
+		if msg.Err != nil {
+			m.Composer.Error = msg.Err.Error()
+			return m, nil
+		}
+		verb := "Committed"
+		if msg.Amend {
+			verb = "Amended"
+		}
+		m.StatusMessage = fmt.Sprintf("%s %s", verb, msg.Hash[:7])
+		m.Composer = NewComposerModel(m.Width, m.Height)
+		m = m.closeScreen()
+
+		// Show the new commit on the checked out branch, highlighted
+		m.InspectedBranch = m.RepoInfo.CurrentBranch
+		if m.RepoInfo.Detached {
+			m.InspectedBranch = msg.Hash
+		}
+		m.CommitsModel.Highlight = msg.Hash
+		m.SelectCommit = msg.Hash
+		m.setFocus(FocusCommits)
+		m.Loading = true
+		return m, refreshData(m.RepoInfo, m.Config, m.InspectedBranch, true)
+
+	case inspectMsg:
+		m.InspectedBranch = msg.Rev
+		m.Loading = true
+		m.StatusMessage = "Inspecting " + msg.Rev
+		return m, refreshData(m.RepoInfo, m.Config, m.InspectedBranch, true)
+
+	case commitDetailMsg:
+		m.CommitDetail = NewCommitDetailModel(msg.Detail, m.Width, m.Height)
+		m = m.openScreen(ScreenCommitDetail)
+		m.StatusMessage = ""
+		return m, nil
+
+	case fileHistoryMsg:
+		m.FileHistory = NewFileHistoryModel(msg.Path, msg.Rev, msg.Revisions, m.Width, m.Height)
+		m = m.openScreen(ScreenFileHistory)
+		m.StatusMessage = ""
+		return m, nil
+
+	case blameMsg:
+		m.Blame = NewBlameModel(msg.Label, msg.Blame, m.Width, m.Height)
+		m = m.openScreen(ScreenBlame)
+		m.StatusMessage = ""
+		return m, nil
+
+	case compareMsg:
+		m.Compare = NewCompareModel(msg.A, msg.B, msg.Comparison, m.Width, m.Height)
+		m = m.openScreen(ScreenCompare)
+		m.StatusMessage = ""
+		return m, nil
+
+	case checkoutBlockedMsg:
+		m.Loading = false
+		m.CheckingOut = ""
+		m.Checkout = CheckoutModal{Plan: msg.Plan}
+		m.StatusMessage = ""
+		return m, nil
+
+	case forecastMsg:
+		if m.BranchesModel.Forecasts == nil {
+			m.BranchesModel.Forecasts = make(map[string]*git.MergeForecast)
+		}
+		m.BranchesModel.Forecasts[msg.Branch] = msg.Forecast
+		m.Viewport.SetContent(m.RenderMainContent())
+		if msg.Open {
+			m.Forecast = NewForecastModel(msg.Branch, m.RepoInfo.CurrentBranch, msg.Forecast, m.Width, m.Height)
+			m = m.openScreen(ScreenForecast)
+			m.StatusMessage = ""
+		}
+		return m, nil
+
+	case fileListMsg:
+		m.FileBrowser = NewFileBrowserModel(msg.Rev, msg.Files, m.Width, m.Height)
+		m = m.openScreen(ScreenFileBrowser)
+		m.StatusMessage = ""
+		return m, nil
+
+	case commitFilterMsg:
+		m.CommitsModel.SetFilter(msg.Filter, msg.Commits, msg.More)
+		m.Loading = false
+		switch {
+		case msg.Filter == "":
+			m.StatusMessage = "Filter cleared"
+		case msg.More:
+			m.StatusMessage = fmt.Sprintf("Showing the first %d matching commits, raise commits.show_count to see more", len(msg.Commits))
+		default:
+			m.StatusMessage = fmt.Sprintf("%d matching commits", len(msg.Commits))
+		}
+		m.Viewport.SetContent(m.RenderMainContent())
+		return m, nil
+
+	case stashDetailMsg:
+		m.StashDetail = NewStashDetailModel(msg.Entry, msg.Detail, m.Width, m.Height)
+		m = m.openScreen(ScreenStashDetail)
+		m.StatusMessage = ""
+		return m, nil
+
 	case tea.WindowSizeMsg:
 		m.Width = msg.Width
 		m.Height = msg.Height
+		m.StashDetail.SetSize(msg.Width, msg.Height)
+		m.CommitDetail.SetSize(msg.Width, msg.Height)
+		m.Diff.SetSize(msg.Width, msg.Height)
+		m.FileHistory.SetSize(msg.Width, msg.Height)
+		m.FileBrowser.SetSize(msg.Width, msg.Height)
+		m.Blame.SetSize(msg.Width, msg.Height)
+		m.Compare.SetSize(msg.Width, msg.Height)
+		m.Forecast.SetSize(msg.Width, msg.Height)
+		m.Cleanup.SetSize(msg.Width, msg.Height)
+		m.Staging.SetSize(msg.Width, msg.Height)
+		m.Composer.SetSize(msg.Width, msg.Height)
+		m.Conflict.SetSize(msg.Width, msg.Height)
 		headerHeight := 3
 		footerHeight := 2
 		verticalMarginHeight := headerHeight + footerHeight
@@ -190,18 +526,54 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Preserve selection
 		oldSelected := m.BranchesModel.Selected
+		showRemotes := m.BranchesModel.ShowRemotes
+		marked := m.BranchesModel.Marked
+		forecasts := m.BranchesModel.Forecasts
 		m.BranchesModel = msg.BranchesModel
-		if oldSelected < len(m.BranchesModel.Branches) {
+		m.BranchesModel.ShowRemotes = showRemotes
+		m.BranchesModel.Marked = marked
+		m.BranchesModel.keepForecasts(forecasts, m.RepoInfo.HeadHash)
+		if oldSelected < m.BranchesModel.Len() {
 			m.BranchesModel.Selected = oldSelected
 		}
 
-		if m.Focus == FocusBranches {
-			m.BranchesModel.Active = true
+		// Keep the cursor on the same tag and the sort the user picked
+		oldTag, hadTag := m.TagsModel.SelectedTag()
+		tagOrder := m.TagsModel.Sort
+		m.TagsModel = msg.TagsModel
+		m.TagsModel.SetSort(tagOrder)
+		if hadTag {
+			m.TagsModel.selectTag(oldTag.Name)
 		}
 
+		oldStash := m.StashModel.Selected
+		m.StashModel = msg.StashModel
+		if oldStash < len(m.StashModel.Entries) {
+			m.StashModel.Selected = oldStash
+		}
+
+		oldCommit := m.CommitsModel.Selected
+		showGraph := m.CommitsModel.ShowGraph
+		filter := m.CommitsModel.Filter
+		highlight := m.CommitsModel.Highlight
 		m.CommitsModel = msg.CommitsModel
+		m.CommitsModel.ShowGraph = showGraph
+		m.CommitsModel.Highlight = highlight
+		if oldCommit < len(m.CommitsModel.Visible()) {
+			m.CommitsModel.Selected = oldCommit
+		}
+		if m.SelectCommit != "" {
+			m.CommitsModel.selectCommit(m.SelectCommit)
+			m.SelectCommit = ""
+		}
+
+		m.setFocus(m.Focus)
+
+		oldFile := m.WorkDirModel.Selected
 		m.WorkDirModel = msg.WorkDirModel
-		m.StashModel = msg.StashModel
+		if oldFile < len(m.WorkDirModel.Files) {
+			m.WorkDirModel.Selected = oldFile
+		}
 		if msg.StatsModel != nil {
 			m.StatsModel = *msg.StatsModel
 		}
@@ -210,7 +582,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Loading = false
 		if m.CheckingOut != "" {
 			m.StatusMessage = fmt.Sprintf("Switched to branch: %s", m.RepoInfo.CurrentBranch)
+			if m.CheckoutNote != "" {
+				m.StatusMessage += ", " + m.CheckoutNote
+			}
 			m.CheckingOut = ""
+			m.CheckoutNote = ""
 		} else if m.StatusMessage == "Refreshing..." {
 			m.StatusMessage = "Refreshed"
 		} else if m.StatusMessage == "" {
@@ -222,6 +598,12 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Hard content flush
 		m.Viewport.SetContent(m.RenderMainContent())
 		m.Viewport.GotoTop()
+
+		// The filter survives refreshes, re-run it against the new history
+		if filter != "" {
+			m.CommitsModel.Filter = filter
+			return m, commitFilterCmd(m.RepoInfo.Path, m.InspectedBranch, filter, commitCount(m.Config))
+		}
 		return m, nil
 
 	case errMsg:
@@ -232,6 +614,76 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.Viewport.SetContent(m.RenderMainContent())
 
 	case tea.KeyMsg:
+		if m.Prompt.Active() {
+			switch msg.String() {
+			case "enter":
+				prompt := m.Prompt
+				m.Prompt = PromptModel{}
+				return m, prompt.OnSubmit(strings.TrimSpace(prompt.Input.Value()))
+			case "esc":
+				m.Prompt = PromptModel{}
+				m.StatusMessage = "Cancelled"
+				return m, nil
+			}
+			m.Prompt, cmd = m.Prompt.Update(msg)
+			return m, cmd
+		}
+
+		if m.Confirm.Active() {
+			confirm := m.Confirm
+			m.Confirm = ConfirmModel{}
+			if msg.String() == "y" || msg.String() == "Y" {
+				m.Loading = true
+				m.StatusMessage = "Working..."
+				return m, confirm.OnConfirm
+			}
+			m.StatusMessage = "Cancelled"
+			return m, nil
+		}
+
+		if m.Checkout.Active() {
+			return m.updateCheckoutModal(msg)
+		}
+
+		if m.Screen != ScreenDashboard {
+			return m.updateScreen(msg)
+		}
+
+		if m.Focus == FocusBranches {
+			if model, cmd, ok := m.handleBranchesKey(msg); ok {
+				model.Viewport.SetContent(model.RenderMainContent())
+				return model, cmd
+			}
+		}
+
+		if m.Focus == FocusTags {
+			if model, cmd, ok := m.handleTagsKey(msg); ok {
+				model.Viewport.SetContent(model.RenderMainContent())
+				return model, cmd
+			}
+		}
+
+		if m.Focus == FocusCommits {
+			if model, cmd, ok := m.handleCommitsKey(msg); ok {
+				model.Viewport.SetContent(model.RenderMainContent())
+				return model, cmd
+			}
+		}
+
+		if m.Focus == FocusStash {
+			if model, cmd, ok := m.handleStashKey(msg); ok {
+				model.Viewport.SetContent(model.RenderMainContent())
+				return model, cmd
+			}
+		}
+
+		if m.Focus == FocusWorkDir {
+			if model, cmd, ok := m.handleWorkDirKey(msg); ok {
+				model.Viewport.SetContent(model.RenderMainContent())
+				return model, cmd
+			}
+		}
+
 		switch msg.String() {
 		case "q", "ctrl+c":
 			m.Quitting = true
@@ -240,9 +692,13 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.Loading = true
 			m.StatusMessage = "Refreshing..."
 			return m, refreshData(m.RepoInfo, m.Config, m.InspectedBranch, true) // Force full refresh on 'r'
-		case "?", "/":
+		case "?":
 			m.ShowHelp = !m.ShowHelp
 			return m, nil
+		case "/":
+			m = m.openCommitFilter()
+			m.Viewport.SetContent(m.RenderMainContent())
+			return m, nil
 		case "esc":
 			if m.ShowHelp {
 				m.ShowHelp = false
@@ -250,49 +706,75 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			m.Quitting = true
 			return m, tea.Quit
+		case "g":
+			m.Prompt = NewPromptModel("Inspect revision:", "", func(rev string) tea.Cmd {
+				if rev == "" {
+					return nil
+				}
+				return inspectRevisionCmd(m.RepoInfo.Path, rev)
+			})
+			return m, nil
+		case "b":
+			m.StatusMessage = "Loading files..."
+			return m, fileListCmd(m.RepoInfo.Path, m.InspectedBranch)
 		case "tab":
-			// Toggle focus
-			if m.Focus == FocusNone {
-				m.Focus = FocusBranches
-				m.BranchesModel.Active = true
-			} else {
-				m.Focus = FocusNone
-				m.BranchesModel.Active = false
-			}
+			// Cycle focus between the scrollable dashboard and the interactive panels
+			m.setFocus(m.nextFocus())
 			m.Viewport.SetContent(m.RenderMainContent())
+			if m.Focus == FocusBranches {
+				return m, m.forecastSelected(false)
+			}
 			return m, nil
 
 		case "up", "k":
 			if m.Focus == FocusBranches {
 				m.BranchesModel.Previous()
-				m.InspectedBranch = m.BranchesModel.Branches[m.BranchesModel.Selected].Name
+				return m.inspectSelectedBranch()
+			}
+			if m.Focus == FocusStash {
+				m.StashModel.Previous()
 				m.Viewport.SetContent(m.RenderMainContent())
-				return m, refreshData(m.RepoInfo, m.Config, m.InspectedBranch, true)
+				return m, nil
 			}
 		case "down", "j":
 			if m.Focus == FocusBranches {
 				m.BranchesModel.Next()
-				m.InspectedBranch = m.BranchesModel.Branches[m.BranchesModel.Selected].Name
+				return m.inspectSelectedBranch()
+			}
+			if m.Focus == FocusStash {
+				m.StashModel.Next()
 				m.Viewport.SetContent(m.RenderMainContent())
-				return m, refreshData(m.RepoInfo, m.Config, m.InspectedBranch, true)
+				return m, nil
+			}
+		case "R":
+			if m.Focus == FocusBranches {
+				m.BranchesModel.ToggleRemotes()
+				return m.inspectSelectedBranch()
 			}
 		case "f":
 			if m.Focus == FocusBranches {
-				b := m.BranchesModel.Branches[m.BranchesModel.Selected]
-				m.Loading = true
-				m.StatusMessage = fmt.Sprintf("Force checking out %s...", b.Name)
-				m.CheckingOut = b.Name
-				m.RefreshTries = 0
-				m.Spinner = 0
-				m.Viewport.SetContent(m.RenderMainContent()) // Update viewport immediately
-				// Start spinner animation and checkout
-				return m, tea.Batch(
-					tea.Tick(100*time.Millisecond, func(t time.Time) tea.Msg { return checkoutTickMsg{} }),
-					checkoutCmd(m.RepoInfo.Path, b.Name, true),
-				)
+				b, ok := m.BranchesModel.SelectedBranch()
+				if !ok {
+					return m, nil
+				}
+				if b.IsRemote {
+					m.StatusMessage = fmt.Sprintf("%s is a remote-tracking branch; it can only be inspected", b.Name)
+					return m, nil
+				}
+				if m, blocked := m.blockedByOperation("force checkout"); blocked {
+					return m, nil
+				}
+				return m.startCheckout(b.Name, fmt.Sprintf("Force checking out %s...", b.Name),
+					checkoutCmd(m.RepoInfo.Path, b.Name, true))
 			}
 		case "enter":
-			// Enter does nothing now to prevent accidental checkouts
+			// Enter never checks out, it only opens inspection views
+			if m.Focus == FocusStash {
+				if e, ok := m.StashModel.SelectedEntry(); ok {
+					m.StatusMessage = fmt.Sprintf("Loading stash@{%d}...", e.ID)
+					return m, stashDetailCmd(m.RepoInfo.Path, e)
+				}
+			}
 			return m, nil
 		}
 	}
@@ -307,6 +789,162 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// inspectSelectedBranch points the commit and stats panels at the highlighted branch
+func (m Model) inspectSelectedBranch() (tea.Model, tea.Cmd) {
+	m.Viewport.SetContent(m.RenderMainContent())
+	b, ok := m.BranchesModel.SelectedBranch()
+	if !ok || b.Name == m.InspectedBranch {
+		return m, m.forecastSelected(false)
+	}
+	m.InspectedBranch = b.Name
+	return m, tea.Batch(refreshData(m.RepoInfo, m.Config, m.InspectedBranch, true), m.forecastSelected(false))
+}
+
+// updateScreen handles keys while a full-screen view is open
+func (m Model) updateScreen(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	key := msg.String()
+	switch {
+	case key == "ctrl+c":
+		m.Quitting = true
+		return m, tea.Quit
+	case key == "esc", key == "q" && m.Screen != ScreenFileBrowser && m.Screen != ScreenComposer: // Both take typed text
+		return m.closeScreen(), nil
+	}
+
+	var cmd tea.Cmd
+	switch m.Screen {
+	case ScreenStashDetail:
+		if msg.String() == "enter" {
+			return m.openDiff(fmt.Sprintf("stash@{%d}", m.StashDetail.Entry.ID), m.StashDetail.Diff), nil
+		}
+		m.StashDetail, cmd = m.StashDetail.Update(msg)
+	case ScreenCommitDetail:
+		if msg.String() == "enter" && m.CommitDetail.Detail != nil {
+			return m.openDiff("Commit "+m.CommitDetail.Detail.Hash[:7], m.CommitDetail.Diff), nil
+		}
+		m.CommitDetail, cmd = m.CommitDetail.Update(msg)
+	case ScreenDiff:
+		m.Diff, cmd = m.Diff.Update(msg)
+	case ScreenFileHistory:
+		if key == "enter" {
+			if rev, ok := m.FileHistory.SelectedRevision(); ok {
+				m.StatusMessage = fmt.Sprintf("Loading %s...", rev.Hash[:7])
+				return m, commitDetailCmd(m.RepoInfo.Path, rev.Hash)
+			}
+			return m, nil
+		}
+		if key == "B" {
+			if rev, ok := m.FileHistory.SelectedRevision(); ok {
+				m.StatusMessage = fmt.Sprintf("Blaming %s...", rev.Path)
+				return m, blameCmd(m.RepoInfo.Path, rev.Hash, rev.Path)
+			}
+			return m, nil
+		}
+		m.FileHistory = m.FileHistory.Update(msg)
+	case ScreenFileBrowser:
+		if key == "enter" || key == "ctrl+b" {
+			f, ok := m.FileBrowser.SelectedFile()
+			if !ok {
+				return m, nil
+			}
+			if key == "ctrl+b" {
+				return m, blameCmd(m.RepoInfo.Path, m.FileBrowser.Rev, f)
+			}
+			return m, fileHistoryCmd(m.RepoInfo.Path, m.FileBrowser.Rev, f)
+		}
+		m.FileBrowser, cmd = m.FileBrowser.Update(msg)
+	case ScreenBlame:
+		if key == "enter" {
+			if l, ok := m.Blame.SelectedLine(); ok {
+				m.StatusMessage = fmt.Sprintf("Loading %s...", l.Hash[:7])
+				return m, commitDetailCmd(m.RepoInfo.Path, l.Hash)
+			}
+			return m, nil
+		}
+		m.Blame = m.Blame.Update(msg)
+	case ScreenCompare:
+		switch key {
+		case "enter":
+			if c, ok := m.Compare.SelectedCommit(); ok {
+				m.StatusMessage = fmt.Sprintf("Loading %s...", c.Hash[:7])
+				return m, commitDetailCmd(m.RepoInfo.Path, c.Hash)
+			}
+			return m, nil
+		case "d":
+			return m.openDiff(fmt.Sprintf("%s...%s", inspectLabel(m.Compare.A), inspectLabel(m.Compare.B)), m.Compare.Diff), nil
+		}
+		m.Compare = m.Compare.Update(msg)
+	case ScreenForecast:
+		m.Forecast, cmd = m.Forecast.Update(msg)
+	case ScreenCleanup:
+		switch key {
+		case "d":
+			if batch := m.Cleanup.Batch(); len(batch) > 0 {
+				m.Confirm = deleteBatchConfirm(m.RepoInfo.Path, batch)
+			}
+			return m, nil
+		case "u":
+			if len(m.Cleanup.Undo) == 0 {
+				return m, nil
+			}
+			m.Cleanup.Summary = "Restoring..."
+			return m, cleanupRestoreCmd(m.RepoInfo.Path, m.Cleanup.Undo)
+		}
+		m.Cleanup = m.Cleanup.Update(msg)
+	case ScreenStaging:
+		if key == "enter" {
+			lines := m.Staging.Selection()
+			if len(lines) == 0 {
+				return m, nil
+			}
+			m.Staging.Summary = "Working..."
+			return m, stageLinesCmd(m.RepoInfo.Path, renameOptions(m.Config), m.Staging.Hunks.Path, m.Staging.Side == sideStaged, lines)
+		}
+		m.Staging = m.Staging.Update(msg)
+	case ScreenComposer:
+		switch key {
+		case "ctrl+s":
+			m.Composer.Error = ""
+			return m, createCommitCmd(m.RepoInfo.Path, m.Composer.Editor.Value(), m.Composer.Amend)
+		case "ctrl+t":
+			m.Composer.ToggleAmend()
+			return m, nil
+		}
+		m.Composer, cmd = m.Composer.Update(msg)
+	case ScreenConflict:
+		m.Conflict, cmd = m.Conflict.Update(msg)
+	}
+	return m, cmd
+}
+
+// openScreen shows a full-screen view; closing it returns to the current one
+func (m Model) openScreen(s Screen) Model {
+	if m.Screen != s {
+		m.Back = append(m.Back, m.Screen)
+	}
+	m.Screen = s
+	return m
+}
+
+// closeScreen goes back to the screen the current one was opened from
+func (m Model) closeScreen() Model {
+	m.Screen = ScreenDashboard
+	if n := len(m.Back); n > 0 {
+		m.Screen = m.Back[n-1]
+		m.Back = m.Back[:n-1]
+	}
+	if m.Screen == ScreenDashboard {
+		m.Viewport.SetContent(m.RenderMainContent())
+	}
+	return m
+}
+
+// openDiff shows files in the diff viewer; closing it returns to the current screen
+func (m Model) openDiff(title string, files []git.FileDiff) Model {
+	m.Diff = NewDiffViewModel(title, files, m.Width, m.Height)
+	return m.openScreen(ScreenDiff)
+}
+
 func (m Model) RenderMainContent() string {
 	panelWidth := m.Width - 4
 	if panelWidth < 40 {
@@ -317,6 +955,7 @@ func (m Model) RenderMainContent() string {
 		lipgloss.Left,
 		"\n",
 		m.BranchesModel.View(panelWidth, m.Loading, m.CheckingOut, m.Spinner),
+		m.TagsModel.View(panelWidth),
 		m.CommitsModel.View(panelWidth),
 		m.StashModel.View(panelWidth),
 		m.StatsModel.View(panelWidth),
@@ -324,6 +963,12 @@ func (m Model) RenderMainContent() string {
 	)
 }
 
+// inspectingHead reports whether the inspected revision is the checked out one
+func (m Model) inspectingHead() bool {
+	return m.InspectedBranch == m.RepoInfo.CurrentBranch ||
+		(m.RepoInfo.Detached && m.InspectedBranch == m.RepoInfo.HeadHash)
+}
+
 func (m Model) View() string {
 	if m.Quitting {
 		return ""
@@ -333,19 +978,55 @@ func (m Model) View() string {
 		return m.helpView()
 	}
 
+	if m.Checkout.Active() {
+		return m.checkoutView()
+	}
+
+	if m.Confirm.Active() && len(m.Confirm.Details) > 0 {
+		return m.confirmDialog()
+	}
+
+	switch m.Screen {
+	case ScreenStashDetail:
+		return m.StashDetail.View()
+	case ScreenCommitDetail:
+		return m.CommitDetail.View()
+	case ScreenDiff:
+		return m.Diff.View()
+	case ScreenFileHistory:
+		return m.FileHistory.View()
+	case ScreenFileBrowser:
+		return m.FileBrowser.View()
+	case ScreenBlame:
+		return m.Blame.View()
+	case ScreenCompare:
+		return m.Compare.View()
+	case ScreenForecast:
+		return m.Forecast.View()
+	case ScreenCleanup:
+		return m.Cleanup.View()
+	case ScreenStaging:
+		return m.Staging.View()
+	case ScreenComposer:
+		return m.Composer.View()
+	case ScreenConflict:
+		return m.Conflict.View()
+	}
+
 	var s strings.Builder
 
 	// Header
 	inspectedText := ""
-	if m.InspectedBranch != m.RepoInfo.CurrentBranch {
-		inspectedText = StyleDim.Render(" • Inspecting: ") + StyleHeader.Render(m.InspectedBranch)
+	if !m.inspectingHead() {
+		inspectedText = StyleDim.Render(" • Inspecting: ") + StyleHeader.Render(inspectLabel(m.InspectedBranch))
 	}
 
 	header := lipgloss.JoinVertical(lipgloss.Left,
 		StyleTitle.Render("GitDash"),
-		fmt.Sprintf(" • %s • %s%s",
+		fmt.Sprintf(" • %s • %s%s%s",
 			m.RepoInfo.Path,
 			StyleSelected.Render(" "+m.RepoInfo.CurrentBranch), // Branch icon and highlight
+			m.operationBanner(),
 			inspectedText),
 		"\n",
 	)
@@ -360,9 +1041,17 @@ func (m Model) View() string {
 		spinner = spinnerChars[m.Spinner] + " "
 	}
 
-	helpText := "Press 'q' to quit, 'r' to refresh, '?' for help, 'Tab' to focus"
+	helpText := "Press 'q' to quit, 'r' to refresh, '?' for help, 'g' go to revision, 'b' files, 'Tab' to focus"
 	if m.Focus == FocusBranches {
-		helpText += " • '↑/↓' inspect, 'f' force checkout"
+		helpText += " • '↑/↓' inspect, 'R' remotes, 'o' checkout, 'O' checkout with auto-stash, 'f' force checkout, 'n' new, 'e' rename, 'D' delete, 'u' upstream, 'C' cleanup, 'm' mark, 'c' compare with mark, 'M' merge forecast"
+	} else if m.Focus == FocusTags {
+		helpText += " • '↑/↓' inspect tag, 's' sort by semver/date"
+	} else if m.Focus == FocusCommits {
+		helpText += " • '↑/↓' select, 'Enter' open commit, 'G' graph, '/' filter"
+	} else if m.Focus == FocusStash {
+		helpText += " • 'Enter' inspect, 's/S' stash (+untracked), 'a' apply, 'p' pop, 'd' drop"
+	} else if m.Focus == FocusWorkDir {
+		helpText += " • '↑/↓' select, 'Space' stage/unstage, 'a' all, 'd' directory, 'Enter' hunks, 'c' commit, 'h' file history, 'B' blame"
 	} else {
 		helpText += " • '↑/↓' to scroll"
 	}
@@ -374,6 +1063,12 @@ func (m Model) View() string {
 	}
 
 	footer := StyleDim.Render("\n" + spinner + helpText)
+	if m.Confirm.Active() {
+		footer = "\n " + m.Confirm.View()
+	}
+	if m.Prompt.Active() {
+		footer = "\n " + m.Prompt.View()
+	}
 	s.WriteString(footer)
 
 	return s.String()
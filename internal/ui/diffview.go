@@ -0,0 +1,287 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sergi/go-diff/diffmatchpatch"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// DiffViewModel is the full-screen diff viewer shared by every view that
+// shows changes. It scrolls freely and jumps between hunks and files.
+type DiffViewModel struct {
+	Title    string
+	Files    []git.FileDiff
+	WordDiff bool // Highlight the changed words of modified lines
+	Viewport viewport.Model
+
+	fileOffsets []int // First rendered line of each file
+	hunkOffsets []int // First rendered line of each hunk, in order
+	width       int
+}
+
+func NewDiffViewModel(title string, files []git.FileDiff, width, height int) DiffViewModel {
+	m := DiffViewModel{
+		Title:    title,
+		Files:    files,
+		WordDiff: true,
+		Viewport: viewport.New(0, 0),
+	}
+	m.Viewport.SetHorizontalStep(8)
+	m.SetSize(width, height)
+	return m
+}
+
+// SetSize resizes the scrollable area, leaving room for the title and footer
+func (m *DiffViewModel) SetSize(width, height int) {
+	m.width = width
+	m.Viewport.Width = width
+	m.Viewport.Height = height - 4
+	if m.Viewport.Height < 1 {
+		m.Viewport.Height = 1
+	}
+	m.render()
+}
+
+func (m *DiffViewModel) render() {
+	var lines []string
+	lines, m.fileOffsets, m.hunkOffsets = renderDiff(m.Files, m.width, m.WordDiff)
+	m.Viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+func (m DiffViewModel) Update(msg tea.Msg) (DiffViewModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch msg.String() {
+		case "n":
+			m.jump(m.hunkOffsets, 1)
+			return m, nil
+		case "N":
+			m.jump(m.hunkOffsets, -1)
+			return m, nil
+		case "]", "tab":
+			m.jump(m.fileOffsets, 1)
+			return m, nil
+		case "[", "shift+tab":
+			m.jump(m.fileOffsets, -1)
+			return m, nil
+		case "w":
+			m.WordDiff = !m.WordDiff
+			offset := m.Viewport.YOffset
+			m.render()
+			m.Viewport.SetYOffset(offset)
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.Viewport, cmd = m.Viewport.Update(msg)
+	return m, cmd
+}
+
+// jump scrolls to the next (dir 1) or previous (dir -1) offset from the top line
+func (m *DiffViewModel) jump(offsets []int, dir int) {
+	top := m.Viewport.YOffset
+	if dir > 0 {
+		for _, o := range offsets {
+			if o > top {
+				m.Viewport.SetYOffset(o)
+				return
+			}
+		}
+		return
+	}
+	for i := len(offsets) - 1; i >= 0; i-- {
+		if offsets[i] < top {
+			m.Viewport.SetYOffset(offsets[i])
+			return
+		}
+	}
+}
+
+// currentFile is the index of the file shown at the top of the viewport
+func (m DiffViewModel) currentFile() int {
+	return max(sort.SearchInts(m.fileOffsets, m.Viewport.YOffset+1)-1, 0)
+}
+
+func (m DiffViewModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(StyleTitle.Render(m.Title))
+	s.WriteString("\n")
+	s.WriteString(m.Viewport.View())
+
+	position := ""
+	if len(m.Files) > 0 {
+		i := m.currentFile()
+		position = fmt.Sprintf("file %d/%d %s • ", i+1, len(m.Files), m.Files[i].Path())
+	}
+	s.WriteString(StyleDim.Render(fmt.Sprintf("\n %s'n/N' hunk, '[/]' file, 'w' words, '←/→' pan, 'Esc' back • %3.f%%", position, m.Viewport.ScrollPercent()*100)))
+
+	return s.String()
+}
+
+// renderDiff renders files as unified diff lines with line numbers. It also
+// returns where each file and hunk starts so callers can jump to them.
+func renderDiff(files []git.FileDiff, width int, wordDiff bool) (lines []string, fileOffsets, hunkOffsets []int) {
+	if len(files) == 0 {
+		return []string{StyleDim.Render("   No changes")}, nil, nil
+	}
+
+	rule := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(strings.Repeat("─", max(width, 1)))
+	for i, f := range files {
+		if i > 0 {
+			lines = append(lines, "")
+		}
+		fileOffsets = append(fileOffsets, len(lines))
+		lines = append(lines, renderFileHeader(f)...)
+		lines = append(lines, rule)
+
+		switch {
+		case f.Binary:
+			lines = append(lines, StyleDim.Render("   Binary file, contents not shown"))
+			continue
+		case len(f.Hunks) == 0:
+			lines = append(lines, StyleDim.Render("   No content changes"))
+			continue
+		}
+
+		for _, h := range f.Hunks {
+			hunkOffsets = append(hunkOffsets, len(lines))
+			lines = append(lines, StyleDiffHunk.Render(h.Header()))
+			lines = append(lines, renderHunkLines(h, wordDiff)...)
+		}
+	}
+	return lines, fileOffsets, hunkOffsets
+}
+
+func renderFileHeader(f git.FileDiff) []string {
+	statusColors := map[string]lipgloss.Color{"A": ColorSuccess, "M": ColorWarning, "D": ColorError, "R": ColorInfo}
+	status := lipgloss.NewStyle().Foreground(statusColors[f.Status()]).Bold(true).Render(f.Status())
+
+	header := []string{fmt.Sprintf("%s %s", status, StyleHeader.Render(f.Path()))}
+	switch f.Status() {
+	case "R":
+		header[0] = fmt.Sprintf("%s %s", status, StyleHeader.Render(f.OldPath+" → "+f.NewPath))
+	case "A":
+		header = append(header, StyleDim.Render(fmt.Sprintf("  new file, mode %o", uint32(f.NewMode))))
+	case "D":
+		header = append(header, StyleDim.Render(fmt.Sprintf("  deleted file, mode %o", uint32(f.OldMode))))
+	}
+	if f.ModeChanged() {
+		header = append(header, StyleDim.Render(fmt.Sprintf("  mode %o → %o", uint32(f.OldMode), uint32(f.NewMode))))
+	}
+	return header
+}
+
+// renderHunkLines renders the lines of one hunk with old/new line numbers.
+// With wordDiff, each removed line paired with an added line in the same
+// block has its changed words highlighted.
+func renderHunkLines(h git.Hunk, wordDiff bool) []string {
+	var out []string
+	lines := h.Lines
+
+	for i := 0; i < len(lines); {
+		if lines[i].Kind != git.DiffDel {
+			out = append(out, renderDiffLine(lines[i], nil))
+			i++
+			continue
+		}
+
+		// A block of removals followed by additions is a modification
+		delStart := i
+		for i < len(lines) && lines[i].Kind == git.DiffDel {
+			i++
+		}
+		addStart := i
+		for i < len(lines) && lines[i].Kind == git.DiffAdd {
+			i++
+		}
+		dels, adds := lines[delStart:addStart], lines[addStart:i]
+
+		delWords := make([][]diffmatchpatch.Diff, len(dels))
+		addWords := make([][]diffmatchpatch.Diff, len(adds))
+		if wordDiff {
+			for k := 0; k < len(dels) && k < len(adds); k++ {
+				if d := wordDiffs(dels[k].Text, adds[k].Text); d != nil {
+					delWords[k], addWords[k] = d, d
+				}
+			}
+		}
+
+		for k, l := range dels {
+			out = append(out, renderDiffLine(l, delWords[k]))
+		}
+		for k, l := range adds {
+			out = append(out, renderDiffLine(l, addWords[k]))
+		}
+	}
+	return out
+}
+
+// wordDiffs diffs two versions of a line, or returns nil when they have too
+// little in common for highlighting to help
+func wordDiffs(a, b string) []diffmatchpatch.Diff {
+	dmp := diffmatchpatch.New()
+	diffs := dmp.DiffCleanupSemantic(dmp.DiffMain(a, b, false))
+
+	common := 0
+	for _, d := range diffs {
+		if d.Type == diffmatchpatch.DiffEqual {
+			common += len(d.Text)
+		}
+	}
+	if common*3 < max(len(a), len(b)) {
+		return nil
+	}
+	return diffs
+}
+
+func renderDiffLine(l git.DiffLine, words []diffmatchpatch.Diff) string {
+	number := func(n int) string {
+		if n == 0 {
+			return ""
+		}
+		return fmt.Sprint(n)
+	}
+	gutter := StyleDim.Render(fmt.Sprintf("%5s %5s │", number(l.OldLine), number(l.NewLine)))
+
+	style, wordStyle, skip := StyleNormal, StyleNormal, diffmatchpatch.Operation(-1)
+	switch l.Kind {
+	case git.DiffAdd:
+		style, wordStyle, skip = StyleDiffAdd, StyleDiffAddWord, diffmatchpatch.DiffDelete
+	case git.DiffDel:
+		style, wordStyle, skip = StyleDiffDel, StyleDiffDelWord, diffmatchpatch.DiffInsert
+	}
+
+	text := style.Render(string(l.Kind) + expandTabs(l.Text))
+	if words != nil {
+		var b strings.Builder
+		b.WriteString(style.Render(string(l.Kind)))
+		for _, d := range words {
+			switch d.Type {
+			case skip:
+				continue
+			case diffmatchpatch.DiffEqual:
+				b.WriteString(style.Render(expandTabs(d.Text)))
+			default:
+				b.WriteString(wordStyle.Render(expandTabs(d.Text)))
+			}
+		}
+		text = b.String()
+	}
+
+	line := gutter + text
+	if l.NoNewline {
+		line += StyleDim.Render("  \\ No newline at end of file")
+	}
+	return line
+}
+
+func expandTabs(s string) string {
+	return strings.ReplaceAll(s, "\t", "    ")
+}
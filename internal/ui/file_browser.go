@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// FileBrowserModel lists the files of a revision to pick one for its history.
+// Typing narrows the list to paths containing the text.
+type FileBrowserModel struct {
+	Rev      string
+	Files    []string
+	Filter   textinput.Model
+	Matches  []string
+	Selected int
+	width    int
+	height   int
+}
+
+type fileListMsg struct {
+	Rev   string
+	Files []string
+}
+
+func fileListCmd(repoPath, rev string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		hash, err := git.ResolveRevision(r, rev)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		files, err := git.ListFiles(r, hash)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return fileListMsg{Rev: rev, Files: files}
+	}
+}
+
+func NewFileBrowserModel(rev string, files []string, width, height int) FileBrowserModel {
+	filter := textinput.New()
+	filter.Prompt = "Find: "
+	filter.PromptStyle = StyleSelected
+	filter.Placeholder = "type to filter paths"
+	filter.Focus()
+
+	return FileBrowserModel{
+		Rev:     rev,
+		Files:   files,
+		Filter:  filter,
+		Matches: files,
+		width:   width,
+		height:  height,
+	}
+}
+
+func (m *FileBrowserModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m FileBrowserModel) rows() int {
+	return max(m.height-7, 1)
+}
+
+// SelectedFile returns the highlighted path, if any
+func (m FileBrowserModel) SelectedFile() (string, bool) {
+	if m.Selected < 0 || m.Selected >= len(m.Matches) {
+		return "", false
+	}
+	return m.Matches[m.Selected], true
+}
+
+func (m FileBrowserModel) Update(msg tea.KeyMsg) (FileBrowserModel, tea.Cmd) {
+	switch msg.String() {
+	case "up":
+		m.Selected--
+	case "down":
+		m.Selected++
+	case "pgup":
+		m.Selected -= m.rows()
+	case "pgdown":
+		m.Selected += m.rows()
+	default:
+		var cmd tea.Cmd
+		m.Filter, cmd = m.Filter.Update(msg)
+		m.match()
+		return m, cmd
+	}
+	m.Selected = max(min(m.Selected, len(m.Matches)-1), 0)
+	return m, nil
+}
+
+// match narrows the list to the paths containing every word of the filter
+func (m *FileBrowserModel) match() {
+	words := strings.Fields(strings.ToLower(m.Filter.Value()))
+	m.Matches = nil
+	for _, f := range m.Files {
+		lower := strings.ToLower(f)
+		ok := true
+		for _, w := range words {
+			if !strings.Contains(lower, w) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			m.Matches = append(m.Matches, f)
+		}
+	}
+	m.Selected = 0
+}
+
+func (m FileBrowserModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(StyleTitle.Render("Files"))
+	s.WriteString("\n")
+	s.WriteString(StyleDim.Render(fmt.Sprintf(" at %s • %d of %d", inspectLabel(m.Rev), len(m.Matches), len(m.Files))))
+	s.WriteString("\n ")
+	s.WriteString(m.Filter.View())
+	s.WriteString("\n\n")
+
+	rows := m.rows()
+	top := 0
+	if m.Selected >= rows {
+		top = m.Selected - rows + 1
+	}
+	end := min(top+rows, len(m.Matches))
+
+	if len(m.Matches) == 0 {
+		s.WriteString(StyleDim.Render("   No matching files"))
+		s.WriteString("\n")
+		end = top + 1
+	}
+	for i := top; i < end && i < len(m.Matches); i++ {
+		line := "  " + m.Matches[i]
+		if i == m.Selected {
+			line = StyleSelected.Render("▶ " + m.Matches[i])
+		}
+		s.WriteString(lipgloss.NewStyle().MaxWidth(m.width).Render(line))
+		s.WriteString("\n")
+	}
+	for i := end - top; i < rows; i++ {
+		s.WriteString("\n")
+	}
+
+	s.WriteString(StyleDim.Render("\n '↑/↓' select, 'Enter' file history, 'Ctrl+B' blame, 'Esc' back"))
+	return s.String()
+}
@@ -0,0 +1,164 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// FileHistoryModel is the full-screen list of commits that changed one file
+type FileHistoryModel struct {
+	Path      string
+	Rev       string // Revision the history starts from
+	Revisions []git.FileRevision
+	Selected  int
+	width     int
+	height    int
+}
+
+type fileHistoryMsg struct {
+	Path      string
+	Rev       string
+	Revisions []git.FileRevision
+}
+
+func fileHistoryCmd(repoPath, rev, file string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		hash, err := git.ResolveRevision(r, rev)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		revisions, err := git.FileHistory(r, file, hash)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return fileHistoryMsg{Path: file, Rev: rev, Revisions: revisions}
+	}
+}
+
+func NewFileHistoryModel(path, rev string, revisions []git.FileRevision, width, height int) FileHistoryModel {
+	return FileHistoryModel{
+		Path:      path,
+		Rev:       rev,
+		Revisions: revisions,
+		width:     width,
+		height:    height,
+	}
+}
+
+func (m *FileHistoryModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+// rows is how many revisions fit between the title and the footer
+func (m FileHistoryModel) rows() int {
+	return max(m.height-6, 1)
+}
+
+// SelectedRevision returns the highlighted revision, if any
+func (m FileHistoryModel) SelectedRevision() (git.FileRevision, bool) {
+	if m.Selected < 0 || m.Selected >= len(m.Revisions) {
+		return git.FileRevision{}, false
+	}
+	return m.Revisions[m.Selected], true
+}
+
+func (m FileHistoryModel) Update(msg tea.KeyMsg) FileHistoryModel {
+	last := len(m.Revisions) - 1
+	switch msg.String() {
+	case "up", "k":
+		m.Selected--
+	case "down", "j":
+		m.Selected++
+	case "pgup", "b":
+		m.Selected -= m.rows()
+	case "pgdown", "f", " ":
+		m.Selected += m.rows()
+	case "home", "g":
+		m.Selected = 0
+	case "end", "G":
+		m.Selected = last
+	}
+	m.Selected = max(min(m.Selected, last), 0)
+	return m
+}
+
+func (m FileHistoryModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(StyleTitle.Render("History of " + m.Path))
+	s.WriteString("\n")
+	s.WriteString(StyleDim.Render(fmt.Sprintf(" at %s • %d commits, following renames", inspectLabel(m.Rev), len(m.Revisions))))
+	s.WriteString("\n\n")
+
+	// Keep the selection on screen
+	rows := m.rows()
+	top := 0
+	if m.Selected >= rows {
+		top = m.Selected - rows + 1
+	}
+	end := min(top+rows, len(m.Revisions))
+
+	for i := top; i < end; i++ {
+		s.WriteString(m.renderRevision(i))
+		s.WriteString("\n")
+	}
+	for i := end - top; i < rows; i++ {
+		s.WriteString("\n")
+	}
+
+	s.WriteString(StyleDim.Render("\n '↑/↓' select, 'PgUp/PgDn' page, 'Enter' open commit, 'B' blame here, 'Esc' back"))
+	return s.String()
+}
+
+func (m FileHistoryModel) renderRevision(i int) string {
+	rev := m.Revisions[i]
+
+	cursor := "  "
+	subject := strings.Split(rev.Message, "\n")[0]
+	subjectStyle := StyleNormal
+	if i == m.Selected {
+		cursor = "▶ "
+		subjectStyle = StyleSelected.Copy().Underline(true)
+	}
+
+	delta := lipgloss.NewStyle().Foreground(ColorSuccess).Render(fmt.Sprintf("+%-4d", rev.Additions)) +
+		lipgloss.NewStyle().Foreground(ColorError).Render(fmt.Sprintf("-%-4d", rev.Deletions))
+	if rev.Binary {
+		delta = StyleDim.Render(fmt.Sprintf("%-10s", "binary"))
+	}
+
+	var note string
+	switch {
+	case rev.Status == "R":
+		note = StyleDim.Render(fmt.Sprintf("  (renamed from %s)", rev.OldPath))
+	case rev.Status == "A":
+		note = StyleDim.Render("  (added)")
+	}
+	if rev.Path != m.Path && rev.Status != "R" {
+		note += StyleDim.Render("  as " + rev.Path)
+	}
+
+	line := fmt.Sprintf("%s%s %s %s %s %s%s",
+		cursor,
+		StyleSelected.Render(rev.Hash[:7]),
+		StyleDim.Render(fmt.Sprintf("%-15s", humanize.Time(rev.When))),
+		lipgloss.NewStyle().Foreground(ColorInfo).Width(16).MaxWidth(16).Render(rev.Author),
+		delta,
+		subjectStyle.Render(subject),
+		note,
+	)
+	return lipgloss.NewStyle().MaxWidth(m.width).Render(line)
+}
@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// ForecastModel is the full-screen list of what merging a branch into HEAD would do
+type ForecastModel struct {
+	Branch   string
+	Into     string
+	Forecast *git.MergeForecast
+	Viewport viewport.Model
+}
+
+type forecastMsg struct {
+	Branch   string
+	Forecast *git.MergeForecast
+	Open     bool // Show the detail screen, not only the badge
+}
+
+func forecastCmd(repoPath, branch string, open bool) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		head, err := r.Head()
+		if err != nil {
+			return errMsg(err)
+		}
+		theirs, err := git.ResolveRevision(r, branch)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		forecast, err := git.ForecastMerge(r, head.Hash(), theirs)
+		if err != nil {
+			if !open {
+				return nil // Badges are best effort
+			}
+			return errMsg(err)
+		}
+
+		return forecastMsg{Branch: branch, Forecast: forecast, Open: open}
+	}
+}
+
+// forecastSelected forecasts merging the selected branch into HEAD, unless
+// it is HEAD itself or the forecast is still current
+func (m Model) forecastSelected(open bool) tea.Cmd {
+	b, ok := m.BranchesModel.SelectedBranch()
+	if !ok || b.IsCurrent || m.RepoInfo.HeadHash == "" {
+		return nil
+	}
+	if f, ok := m.BranchesModel.Forecasts[b.Name]; ok && f.Ours == m.RepoInfo.HeadHash && f.Theirs == b.Hash && !open {
+		return nil
+	}
+	return forecastCmd(m.RepoInfo.Path, b.Name, open)
+}
+
+// forecastBadge summarises a forecast next to a branch
+func forecastBadge(f *git.MergeForecast) string {
+	switch {
+	case f == nil:
+		return ""
+	case f.UpToDate:
+		return StyleDim.Render(" ≡ merged")
+	case f.FastForward:
+		return lipgloss.NewStyle().Foreground(ColorSuccess).Render(" ⇢ fast-forward")
+	case f.Conflicts() > 0:
+		n := f.Conflicts()
+		label := fmt.Sprintf(" ✗ %d conflicts", n)
+		if n == 1 {
+			label = " ✗ 1 conflict"
+		}
+		return lipgloss.NewStyle().Foreground(ColorError).Render(label)
+	}
+	return lipgloss.NewStyle().Foreground(ColorSuccess).Render(" ✓ merges cleanly")
+}
+
+func NewForecastModel(branch, into string, f *git.MergeForecast, width, height int) ForecastModel {
+	m := ForecastModel{
+		Branch:   branch,
+		Into:     into,
+		Forecast: f,
+		Viewport: viewport.New(0, 0),
+	}
+	m.SetSize(width, height)
+	return m
+}
+
+func (m *ForecastModel) SetSize(width, height int) {
+	m.Viewport.Width = width
+	m.Viewport.Height = max(height-4, 1)
+	m.Viewport.SetContent(m.renderContent())
+}
+
+func (m ForecastModel) Update(msg tea.Msg) (ForecastModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Viewport, cmd = m.Viewport.Update(msg)
+	return m, cmd
+}
+
+func (m ForecastModel) renderContent() string {
+	f := m.Forecast
+	if f == nil {
+		return ""
+	}
+
+	var s strings.Builder
+	base := StyleDim.Render("none, the histories are unrelated")
+	if f.MergeBase != "" {
+		base = StyleSelected.Render(f.MergeBase[:7])
+	}
+	s.WriteString(fmt.Sprintf(" merge-base %s\n\n", base))
+
+	switch {
+	case f.UpToDate:
+		s.WriteString(StyleDim.Render(fmt.Sprintf(" %s is already merged into %s, nothing to do", m.Branch, m.Into)))
+		return s.String()
+	case f.FastForward:
+		s.WriteString(lipgloss.NewStyle().Foreground(ColorSuccess).Render(fmt.Sprintf(" %s has no commits of its own, the merge is a fast-forward", m.Into)))
+		s.WriteString("\n\n")
+	}
+
+	var conflicts, clean []git.ForecastFile
+	for _, file := range f.Files {
+		if file.Conflict {
+			conflicts = append(conflicts, file)
+		} else {
+			clean = append(clean, file)
+		}
+	}
+
+	if len(conflicts) > 0 {
+		s.WriteString(lipgloss.NewStyle().Foreground(ColorError).Bold(true).Render(fmt.Sprintf("Would conflict (%d)", len(conflicts))))
+		s.WriteString("\n")
+		for _, file := range conflicts {
+			s.WriteString(fmt.Sprintf("   %s %s %s\n", lipgloss.NewStyle().Foreground(ColorError).Render("✗"), file.Path, StyleDim.Render("("+file.Reason+")")))
+		}
+		s.WriteString("\n")
+	}
+
+	s.WriteString(StyleHeader.Render(fmt.Sprintf("Merges cleanly (%d)", len(clean))))
+	s.WriteString("\n")
+	if len(clean) == 0 {
+		s.WriteString(StyleDim.Render("   nothing"))
+		s.WriteString("\n")
+	}
+	for _, file := range clean {
+		note := ""
+		if file.BothChanged {
+			note = StyleDim.Render(" (auto-merged, changed on both sides)")
+		}
+		s.WriteString(fmt.Sprintf("   %s %s %s%s\n", lipgloss.NewStyle().Foreground(ColorSuccess).Render("✓"), file.Status, file.Path, note))
+	}
+	return s.String()
+}
+
+func (m ForecastModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(StyleTitle.Render(fmt.Sprintf("Merge %s into %s", inspectLabel(m.Branch), m.Into)))
+	s.WriteString("\n")
+	s.WriteString(m.Viewport.View())
+	s.WriteString(StyleDim.Render(fmt.Sprintf("\n '↑/↓' scroll, 'Esc' back • forecast only, nothing is merged • %3.f%%", m.Viewport.ScrollPercent()*100)))
+	return s.String()
+}
@@ -0,0 +1,133 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// layoutGraph lays commits out in lanes like `git log --graph`. For every
+// commit it returns the row drawn beside it and the row drawn beside its
+// second line. Lanes never shift sideways: a lane that ends leaves a gap that
+// a later branch reuses, so every connection fits on the commit's own row.
+// Parents outside the list keep their lane running to the bottom.
+func layoutGraph(commits []git.Commit) (rows, below []string) {
+	var lanes []string // Hash each lane is waiting for, "" when free
+
+	for _, c := range commits {
+		col := slices.Index(lanes, c.Hash)
+		if col < 0 {
+			// A branch tip: its child is not in the list
+			col = freeLane(lanes, nil)
+			if col == len(lanes) {
+				lanes = append(lanes, "")
+			}
+		}
+
+		before := slices.Clone(lanes)
+
+		// Other lanes waiting for this commit end here (it is a fork point)
+		var merging []int
+		for i, h := range lanes {
+			if i != col && h == c.Hash {
+				merging = append(merging, i)
+				lanes[i] = ""
+			}
+		}
+
+		lanes[col] = ""
+		var forks []int
+		for i, p := range c.Parents {
+			if i == 0 {
+				lanes[col] = p
+				continue
+			}
+			// Extra parents of a merge get a lane that was free before this row
+			k := freeLane(lanes, before)
+			if k == len(lanes) {
+				lanes = append(lanes, "")
+			}
+			lanes[k] = p
+			forks = append(forks, k)
+		}
+
+		width := max(len(before), len(lanes))
+		cells := []rune(strings.Repeat(" ", 2*width))
+		for i, h := range before {
+			if h != "" {
+				cells[2*i] = '│'
+			}
+		}
+		cells[2*col] = '●'
+		for _, j := range merging {
+			connect(cells, col, j, '╯', '╰')
+		}
+		for _, k := range forks {
+			connect(cells, col, k, '╮', '╭')
+		}
+		rows = append(rows, string(cells))
+
+		// Drop free lanes at the right edge so the graph stays narrow
+		for len(lanes) > 0 && lanes[len(lanes)-1] == "" {
+			lanes = lanes[:len(lanes)-1]
+		}
+
+		next := []rune(strings.Repeat(" ", 2*len(lanes)))
+		for i, h := range lanes {
+			if h != "" {
+				next[2*i] = '│'
+			}
+		}
+		below = append(below, string(next))
+	}
+
+	// Pad every row to the widest one so the text after the graph lines up
+	width := 0
+	for _, r := range rows {
+		width = max(width, len([]rune(r)))
+	}
+	for i := range rows {
+		rows[i] = padRunes(rows[i], width)
+		below[i] = padRunes(below[i], width)
+	}
+	return rows, below
+}
+
+// freeLane returns the first lane that is free now and was free in before
+// (nil means no restriction), or len(lanes) when a new lane is needed
+func freeLane(lanes, before []string) int {
+	for i, h := range lanes {
+		if h == "" && (before == nil || i >= len(before) || before[i] == "") {
+			return i
+		}
+	}
+	return len(lanes)
+}
+
+// connect draws a horizontal line from the commit lane to another lane,
+// ending in right or left depending on the side it is on
+func connect(cells []rune, col, lane int, right, left rune) {
+	from, to := 2*col, 2*lane
+	end := right
+	if lane < col {
+		from, to = to, from
+		end = left
+	}
+	for x := from + 1; x < to; x++ {
+		switch cells[x] {
+		case ' ':
+			cells[x] = '─'
+		case '│':
+			cells[x] = '┼'
+		}
+	}
+	cells[2*lane] = end
+}
+
+func padRunes(s string, width int) string {
+	if n := len([]rune(s)); n < width {
+		return s + strings.Repeat(" ", width-n)
+	}
+	return s
+}
@@ -8,12 +8,12 @@ import (
 )
 
 func (m Model) helpView() string {
-	width := 60
-	height := 18
+	width := 74
 
+	// The box grows with its rows, but never past the terminal
 	style := lipgloss.NewStyle().
 		Width(width).
-		Height(height).
+		MaxHeight(m.Height).
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(ColorPrimary).
 		Background(lipgloss.Color("236")).
@@ -22,7 +22,7 @@ func (m Model) helpView() string {
 	var s strings.Builder
 
 	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorSecondary).MarginBottom(1)
-	keyStyle := lipgloss.NewStyle().Foreground(ColorInfo).Bold(true).Width(12)
+	keyStyle := lipgloss.NewStyle().Foreground(ColorInfo).Bold(true).Width(14)
 	descStyle := lipgloss.NewStyle().Foreground(ColorText)
 	guideStyle := lipgloss.NewStyle().Foreground(ColorSubText).Italic(true)
 
@@ -33,15 +33,38 @@ func (m Model) helpView() string {
 	s.WriteString(titleStyle.Render("GitDash - Command Guide"))
 	s.WriteString("\n\n")
 
-	s.WriteString(row("Tab", "Toggle focus (General / Branches)"))
+	s.WriteString(row("Tab", "Cycle focus (General/Branches/Tags/Commits/Stash/Files)"))
 	s.WriteString(row("↑/↓ / k/j", "Scroll OR Inspect highlighted branch"))
+	s.WriteString(row("R", "Show/hide remote-tracking branches"))
+	s.WriteString(row("o", "Checkout, stops if local changes are in the way"))
+	s.WriteString(row("O", "Checkout, stashing and reapplying local changes"))
 	s.WriteString(row("f", "Force Checkout (Discard local changes)"))
+	s.WriteString(row("n / e", "New branch at the inspected commit / rename"))
+	s.WriteString(row("D", "Delete branch, asks again if commits would be lost"))
+	s.WriteString(row("u", "Set or unset the upstream of a branch"))
+	s.WriteString(row("C", "Clean up merged, stale and gone branches"))
+	s.WriteString(row("m / c", "Mark a branch / compare it with the selected"))
+	s.WriteString(row("M", "Forecast merging the branch into HEAD"))
+	s.WriteString(row("G (Commits)", "Toggle the commit graph with ref names"))
+	s.WriteString(row("/", "Filter commits (author: path: since: until: msg:)"))
+	s.WriteString(row("s (Tags)", "Sort tags by semver or date"))
+	s.WriteString(row("Enter", "Open selected commit or stash (files & diff)"))
+	s.WriteString(row("s / S", "Stash changes (S includes untracked)"))
+	s.WriteString(row("a / p", "Apply / Pop selected stash"))
+	s.WriteString(row("d", "Drop selected stash (asks first)"))
+	s.WriteString(row("g", "Go to any revision (HEAD~3, v1.2, origin/x)"))
+	s.WriteString(row("b", "Browse files of the revision, Enter for history"))
+	s.WriteString(row("Space (Files)", "Stage/unstage file; 'a' all, 'd' its directory"))
+	s.WriteString(row("Enter (Files)", "Stage hunks/lines; on a conflict, ours/theirs/base"))
+	s.WriteString(row("c (Files)", "Commit staged changes; Ctrl+T amends, Ctrl+S commits"))
+	s.WriteString(row("h (Files)", "History of the selected working-dir file"))
+	s.WriteString(row("B (Files)", "Blame the file at the inspected revision"))
 	s.WriteString(row("r", "Hard Refresh dashboard"))
 	s.WriteString(row("?", "Close this menu"))
 	s.WriteString(row("q / Esc", "Quit application"))
 
 	s.WriteString("\n")
-	s.WriteString(guideStyle.Render("🔍 Inspection Mode is automatic - move the cursor over\nAny branch to view its commits and stats instantly.\nYour working directory is always protected."))
+	s.WriteString(guideStyle.Render("🔍 Inspection Mode is automatic - move the cursor over\nAny branch to view its commits and stats instantly.\nForce checkout discards local changes, o and O keep them."))
 
 	s.WriteString("\n\n")
 	s.WriteString(StyleDim.Render("Built by DeepMind | Press Esc to return"))
@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+)
+
+// operationsText joins the operations in progress, e.g. "REBASE 3/7 onto main"
+func (m Model) operationsText() string {
+	var ops []string
+	for _, op := range m.RepoInfo.Operations {
+		ops = append(ops, op.String())
+	}
+	return strings.Join(ops, " • ")
+}
+
+// operationBanner is the header badge shown while an operation is in
+// progress, empty otherwise
+func (m Model) operationBanner() string {
+	if len(m.RepoInfo.Operations) == 0 {
+		return ""
+	}
+	return " " + StyleOperation.Render("⚠ "+m.operationsText())
+}
+
+// blockedByOperation refuses an action that moves HEAD, changes branches or
+// their upstreams, or touches the stash or the worktree wholesale while git
+// is halfway through something. The operation has to be finished or aborted
+// with git itself. Staging, committing and read-only views stay available.
+func (m Model) blockedByOperation(action string) (Model, bool) {
+	if len(m.RepoInfo.Operations) == 0 {
+		return m, false
+	}
+	m.StatusMessage = fmt.Sprintf("Cannot %s during %s, continue or abort it with git first", action, m.operationsText())
+	return m, true
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// PromptModel is a one-line text input shown in the footer. Enter hands the
+// value to OnSubmit, Esc cancels.
+type PromptModel struct {
+	Input    textinput.Model
+	OnSubmit func(value string) tea.Cmd
+}
+
+func NewPromptModel(label, value string, onSubmit func(value string) tea.Cmd) PromptModel {
+	input := textinput.New()
+	input.Prompt = label + " "
+	input.PromptStyle = StyleSelected
+	input.SetValue(value)
+	input.CursorEnd()
+	input.Focus()
+
+	return PromptModel{
+		Input:    input,
+		OnSubmit: onSubmit,
+	}
+}
+
+func (p PromptModel) Active() bool {
+	return p.OnSubmit != nil
+}
+
+func (p PromptModel) Update(msg tea.Msg) (PromptModel, tea.Cmd) {
+	var cmd tea.Cmd
+	p.Input, cmd = p.Input.Update(msg)
+	return p, cmd
+}
+
+func (p PromptModel) View() string {
+	return p.Input.View() + StyleDim.Render("  (Enter to confirm, Esc to cancel)")
+}
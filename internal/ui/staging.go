@@ -0,0 +1,334 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// Sides of the staging view
+const (
+	sideUnstaged = iota
+	sideStaged
+)
+
+// StagingModel is the split view of one file's unstaged and staged hunks.
+// Lines or whole hunks move between the two sides, like `git add -p`.
+type StagingModel struct {
+	Hunks   *git.FileHunks
+	Side    int                      // Side with the cursor
+	Cursor  [2]int                   // Index into the changed lines of each side
+	Picked  [2]map[git.DiffLine]bool // Lines picked for the next stage/unstage
+	Summary string                   // Outcome of the last operation
+	width   int
+	height  int
+}
+
+// linePos locates a changed line within a diff
+type linePos struct {
+	hunk, line int
+}
+
+// fileHunksMsg opens or reloads the staging view
+type fileHunksMsg struct {
+	Hunks   *git.FileHunks
+	Status  *git.WorkingDirStatus // Reloaded after staging, nil when opening
+	Message string
+	Open    bool
+}
+
+func fileHunksCmd(repoPath, path string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		hunks, err := git.GetFileHunks(r, path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return fileHunksMsg{Hunks: hunks, Open: true}
+	}
+}
+
+// stageLinesCmd stages (or unstages) lines of a file, then reloads its
+// hunks and the working directory status. A failure is reported in the view.
+func stageLinesCmd(repoPath string, renames git.RenameOptions, path string, unstage bool, lines []git.DiffLine) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		verb := "Staged"
+		if unstage {
+			verb = "Unstaged"
+			err = git.UnstageLines(r, path, lines)
+		} else {
+			err = git.StageLines(r, path, lines)
+		}
+		message := fmt.Sprintf("%s %d line(s)", verb, len(lines))
+		if err != nil {
+			message = lipgloss.NewStyle().Foreground(ColorError).Render(fmt.Sprintf("Error: %v", err))
+		}
+
+		hunks, err := git.GetFileHunks(r, path)
+		if err != nil {
+			return errMsg(err)
+		}
+		status, err := git.GetWorkingDirStatus(r, renames)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return fileHunksMsg{Hunks: hunks, Status: status, Message: message}
+	}
+}
+
+func NewStagingModel(hunks *git.FileHunks, side, width, height int) StagingModel {
+	m := StagingModel{Side: side, width: width, height: height}
+	m.SetHunks(hunks)
+	return m
+}
+
+// SetHunks replaces the diffs, clearing the picks they no longer match
+func (m *StagingModel) SetHunks(hunks *git.FileHunks) {
+	m.Hunks = hunks
+	for side := range m.Picked {
+		m.Picked[side] = make(map[git.DiffLine]bool)
+		m.Cursor[side] = max(min(m.Cursor[side], len(m.changes(side))-1), 0)
+	}
+}
+
+func (m *StagingModel) SetSize(width, height int) {
+	m.width = width
+	m.height = height
+}
+
+func (m StagingModel) diff(side int) git.FileDiff {
+	if side == sideStaged {
+		return m.Hunks.Staged
+	}
+	return m.Hunks.Unstaged
+}
+
+// changes lists the added and removed lines of one side in order
+func (m StagingModel) changes(side int) []linePos {
+	var changes []linePos
+	for h, hunk := range m.diff(side).Hunks {
+		for l, line := range hunk.Lines {
+			if line.Kind != git.DiffContext {
+				changes = append(changes, linePos{h, l})
+			}
+		}
+	}
+	return changes
+}
+
+// current is the changed line under the cursor of the active side
+func (m StagingModel) current() (linePos, bool) {
+	changes := m.changes(m.Side)
+	if len(changes) == 0 {
+		return linePos{}, false
+	}
+	return changes[m.Cursor[m.Side]], true
+}
+
+// Selection is what the next stage/unstage covers: the picked lines of the
+// active side, or the hunk under the cursor when nothing is picked
+func (m StagingModel) Selection() []git.DiffLine {
+	var lines []git.DiffLine
+	for _, p := range m.changes(m.Side) {
+		if l := m.diff(m.Side).Hunks[p.hunk].Lines[p.line]; m.Picked[m.Side][l] {
+			lines = append(lines, l)
+		}
+	}
+	if len(lines) > 0 {
+		return lines
+	}
+
+	cur, ok := m.current()
+	if !ok {
+		return nil
+	}
+	for _, l := range m.diff(m.Side).Hunks[cur.hunk].Lines {
+		if l.Kind != git.DiffContext {
+			lines = append(lines, l)
+		}
+	}
+	return lines
+}
+
+func (m StagingModel) Update(msg tea.KeyMsg) StagingModel {
+	changes := m.changes(m.Side)
+	cursor := m.Cursor[m.Side]
+
+	// jumpHunk moves to the first changed line of the next (dir 1) or previous (dir -1) hunk
+	jumpHunk := func(dir int) {
+		if len(changes) == 0 {
+			return
+		}
+		hunk := changes[cursor].hunk
+		for i := cursor; i >= 0 && i < len(changes); i += dir {
+			if changes[i].hunk != hunk && (dir > 0 || i == 0 || changes[i-1].hunk != changes[i].hunk) {
+				cursor = i
+				return
+			}
+		}
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		cursor--
+	case "down", "j":
+		cursor++
+	case "n":
+		jumpHunk(1)
+	case "N":
+		jumpHunk(-1)
+	case "home", "g":
+		cursor = 0
+	case "end", "G":
+		cursor = len(changes) - 1
+	case "tab", "left", "right":
+		m.Side = 1 - m.Side
+		return m
+	case " ":
+		if len(changes) == 0 {
+			return m
+		}
+		p := changes[cursor]
+		l := m.diff(m.Side).Hunks[p.hunk].Lines[p.line]
+		if m.Picked[m.Side][l] {
+			delete(m.Picked[m.Side], l)
+		} else {
+			m.Picked[m.Side][l] = true
+		}
+		cursor++
+	case "a":
+		// Pick the whole hunk, or drop its picks when it is fully picked
+		cur, ok := m.current()
+		if !ok {
+			return m
+		}
+		var lines []git.DiffLine
+		all := true
+		for _, l := range m.diff(m.Side).Hunks[cur.hunk].Lines {
+			if l.Kind != git.DiffContext {
+				lines = append(lines, l)
+				all = all && m.Picked[m.Side][l]
+			}
+		}
+		for _, l := range lines {
+			if all {
+				delete(m.Picked[m.Side], l)
+			} else {
+				m.Picked[m.Side][l] = true
+			}
+		}
+	}
+	m.Cursor[m.Side] = max(min(cursor, len(changes)-1), 0)
+	return m
+}
+
+func (m StagingModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(StyleTitle.Render("Stage " + m.Hunks.Path))
+	s.WriteString("\n")
+	summary := StyleDim.Render(" Space picks lines, Enter moves the picked lines (or the hunk) to the other side")
+	if m.Summary != "" {
+		summary = " " + m.Summary
+	}
+	s.WriteString(summary)
+	s.WriteString("\n\n")
+
+	paneWidth := max((m.width-3)/2, 10)
+	rows := max(m.height-6, 1)
+	left := m.renderSide(sideUnstaged, paneWidth, rows)
+	right := m.renderSide(sideStaged, paneWidth, rows)
+	divider := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(strings.TrimSuffix(strings.Repeat(" │ \n", rows+1), "\n"))
+	s.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, left, divider, right))
+
+	action := "stage"
+	if m.Side == sideStaged {
+		action = "unstage"
+	}
+	s.WriteString(StyleDim.Render(fmt.Sprintf("\n '↑/↓' line, 'n/N' hunk, 'Tab' side, 'Space' pick line, 'a' pick hunk, 'Enter' %s, 'Esc' back", action)))
+	return s.String()
+}
+
+// renderSide renders one pane: a heading and the hunks, scrolled so the cursor is visible
+func (m StagingModel) renderSide(side, width, rows int) string {
+	f := m.diff(side)
+	active := side == m.Side
+
+	title := fmt.Sprintf("Unstaged (%d hunks)", len(f.Hunks))
+	if side == sideStaged {
+		title = fmt.Sprintf("Staged (%d hunks)", len(f.Hunks))
+	}
+	heading := StyleHeader.Render(title)
+	if active {
+		heading = StyleSelected.Copy().Bold(true).Render("★ " + title)
+	}
+	if n := len(m.Picked[side]); n > 0 {
+		heading += StyleDim.Render(fmt.Sprintf(" • %d picked", n))
+	}
+
+	var lines []string
+	cursorRow := 0
+	switch {
+	case f.Binary:
+		lines = append(lines, StyleDim.Render("   Binary file, stage it whole from the panel"))
+	case len(f.Hunks) == 0 && f.ModeChanged():
+		lines = append(lines, StyleDim.Render(fmt.Sprintf("   Mode %o → %o only, stage it whole from the panel", uint32(f.OldMode), uint32(f.NewMode))))
+	case len(f.Hunks) == 0:
+		lines = append(lines, StyleDim.Render("   No changes"))
+	}
+
+	var cur linePos
+	if changes := m.changes(side); len(changes) > 0 {
+		cur = changes[m.Cursor[side]]
+	}
+	for h, hunk := range f.Hunks {
+		lines = append(lines, "  "+StyleDiffHunk.Render(hunk.Header()))
+		rendered := renderHunkLines(hunk, true)
+		for l, line := range hunk.Lines {
+			pointer, picked := " ", " "
+			if cur == (linePos{h, l}) {
+				cursorRow = len(lines)
+				pointer = StyleDim.Render("›")
+				if active {
+					pointer = StyleSelected.Render("▶")
+				}
+			}
+			if m.Picked[side][line] {
+				picked = lipgloss.NewStyle().Foreground(ColorSuccess).Render("●")
+			}
+			marker := pointer + picked
+			lines = append(lines, marker+rendered[l])
+		}
+	}
+
+	top := 0
+	if cursorRow >= rows {
+		top = cursorRow - rows/2
+	}
+	top = max(min(top, len(lines)-rows), 0)
+	end := min(top+rows, len(lines))
+
+	clip := lipgloss.NewStyle().Width(width).MaxWidth(width)
+	out := []string{clip.Render(heading)}
+	for _, l := range lines[top:end] {
+		out = append(out, clip.Render(l))
+	}
+	for len(out) < rows+1 {
+		out = append(out, clip.Render(""))
+	}
+	return strings.Join(out, "\n")
+}
@@ -4,12 +4,26 @@ import (
 	"fmt"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 	"github.com/dustin/go-humanize"
 	"github.com/sh9336/gitdash/internal/git"
 )
 
 type StashModel struct {
-	Entries []git.StashEntry
+	Entries  []git.StashEntry
+	Selected int
+	Active   bool // Whether this panel is currently active/focused
+}
+
+type stashDetailMsg struct {
+	Entry  git.StashEntry
+	Detail *git.StashDetail
+}
+
+// stashOpDoneMsg carries the outcome of a stash write for the status line
+type stashOpDoneMsg struct {
+	Message string
 }
 
 func NewStashModel(entries []git.StashEntry) StashModel {
@@ -18,29 +32,192 @@ func NewStashModel(entries []git.StashEntry) StashModel {
 	}
 }
 
+func (m *StashModel) Next() {
+	if m.Selected < len(m.Entries)-1 {
+		m.Selected++
+	}
+}
+
+func (m *StashModel) Previous() {
+	if m.Selected > 0 {
+		m.Selected--
+	}
+}
+
+// SelectedEntry returns the highlighted stash entry, if any
+func (m StashModel) SelectedEntry() (git.StashEntry, bool) {
+	if m.Selected < 0 || m.Selected >= len(m.Entries) {
+		return git.StashEntry{}, false
+	}
+	return m.Entries[m.Selected], true
+}
+
+func stashDetailCmd(path string, entry git.StashEntry) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		detail, err := git.GetStashDetail(r, entry.Hash)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return stashDetailMsg{Entry: entry, Detail: detail}
+	}
+}
+
+func stashPushCmd(path string, includeUntracked bool) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		entry, err := git.CreateStash(r, "", includeUntracked)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return stashOpDoneMsg{Message: "Saved " + entry.Message}
+	}
+}
+
+func stashApplyCmd(path string, id int, hash string, pop bool) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		var result *git.StashApplyResult
+		if pop {
+			result, err = git.PopStash(r, id, hash)
+		} else {
+			result, err = git.ApplyStash(r, id, hash)
+		}
+		if err != nil {
+			return errMsg(err)
+		}
+
+		if len(result.Conflicts) > 0 {
+			return stashOpDoneMsg{Message: fmt.Sprintf("stash@{%d} not applied, conflicts in %s (stash kept)", id, summarizePaths(result.Conflicts))}
+		}
+
+		verb := "Applied"
+		if pop {
+			verb = "Popped"
+		}
+		return stashOpDoneMsg{Message: fmt.Sprintf("%s stash@{%d} (%d files)", verb, id, len(result.Updated))}
+	}
+}
+
+func stashDropCmd(path string, id int, hash string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(path)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		if err := git.DropStash(r, id, hash); err != nil {
+			return errMsg(err)
+		}
+
+		return stashOpDoneMsg{Message: fmt.Sprintf("Dropped stash@{%d}", id)}
+	}
+}
+
+// summarizePaths lists the first few paths for a one-line status message
+func summarizePaths(paths []string) string {
+	const shown = 3
+	if len(paths) <= shown {
+		return strings.Join(paths, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(paths[:shown], ", "), len(paths)-shown)
+}
+
+// handleStashKey runs the Stash panel bindings; ok is false for keys it does not own
+func (m Model) handleStashKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "s", "S":
+		if m, blocked := m.blockedByOperation("stash"); blocked {
+			return m, nil, true
+		}
+		includeUntracked := msg.String() == "S"
+		m.Loading = true
+		m.StatusMessage = "Stashing local changes..."
+		return m, stashPushCmd(m.RepoInfo.Path, includeUntracked), true
+	case "a", "p":
+		e, ok := m.StashModel.SelectedEntry()
+		if !ok {
+			return m, nil, true
+		}
+		if m, blocked := m.blockedByOperation(fmt.Sprintf("apply stash@{%d}", e.ID)); blocked {
+			return m, nil, true
+		}
+		pop := msg.String() == "p"
+		m.Loading = true
+		m.StatusMessage = fmt.Sprintf("Applying stash@{%d}...", e.ID)
+		return m, stashApplyCmd(m.RepoInfo.Path, e.ID, e.Hash, pop), true
+	case "d":
+		e, ok := m.StashModel.SelectedEntry()
+		if !ok {
+			return m, nil, true
+		}
+		if m, blocked := m.blockedByOperation(fmt.Sprintf("drop stash@{%d}", e.ID)); blocked {
+			return m, nil, true
+		}
+		m.Confirm = NewConfirmModel(fmt.Sprintf("Drop stash@{%d} \"%s\"?", e.ID, e.Message), stashDropCmd(m.RepoInfo.Path, e.ID, e.Hash))
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
 func (m StashModel) View(width int) string {
 	var s strings.Builder
 
 	// Header
-	s.WriteString(StyleHeader.Render("Stash"))
+	title := fmt.Sprintf("Stash (%d)", len(m.Entries))
+	if m.Active {
+		s.WriteString(StyleSelected.Copy().Bold(true).Render("★ " + title))
+	} else {
+		s.WriteString(StyleHeader.Render(title))
+	}
 	s.WriteString("\n")
 
 	if len(m.Entries) == 0 {
 		s.WriteString(StyleDim.Render("   No stash entries"))
-		return StylePanel.Copy().Width(width).Render(s.String())
+		return m.panelStyle(width).Render(s.String())
 	}
 
-	for _, e := range m.Entries {
+	for i, e := range m.Entries {
 		id := fmt.Sprintf("stash@{%d}", e.ID)
 		timeStr := humanize.Time(e.Timestamp)
 
-		line := fmt.Sprintf(" %s %s %s",
+		cursor := "  "
+		msg := StyleNormal.Render(e.Message)
+		if m.Active && i == m.Selected {
+			cursor = " ▶"
+			msg = StyleSelected.Copy().Underline(true).Render(e.Message)
+		}
+
+		line := fmt.Sprintf("%s %s %s %s",
+			cursor,
 			StyleSelected.Render(id),
-			StyleNormal.Render(e.Message),
+			msg,
 			StyleDim.Render("("+timeStr+")"),
 		)
 		s.WriteString(line + "\n")
 	}
 
-	return StylePanel.Copy().Width(width).Render(s.String())
+	return m.panelStyle(width).Render(s.String())
+}
+
+func (m StashModel) panelStyle(width int) lipgloss.Style {
+	style := StylePanel.Copy().Width(width)
+	if m.Active {
+		style = style.BorderForeground(ColorPrimary)
+	}
+	return style
 }
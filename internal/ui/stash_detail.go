@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// StashDetailModel is the full-screen inspection view of a single stash entry
+type StashDetailModel struct {
+	Entry    git.StashEntry
+	Detail   *git.StashDetail
+	Diff     []git.FileDiff
+	Viewport viewport.Model
+}
+
+func NewStashDetailModel(entry git.StashEntry, detail *git.StashDetail, width, height int) StashDetailModel {
+	m := StashDetailModel{
+		Entry:    entry,
+		Detail:   detail,
+		Viewport: viewport.New(0, 0),
+	}
+	if detail != nil {
+		m.Diff = git.FileDiffs(detail.Patch, git.DefaultContext)
+	}
+	m.SetSize(width, height)
+	return m
+}
+
+// SetSize resizes the scrollable area, leaving room for the title and footer
+func (m *StashDetailModel) SetSize(width, height int) {
+	m.Viewport.Width = width
+	m.Viewport.Height = height - 4
+	if m.Viewport.Height < 1 {
+		m.Viewport.Height = 1
+	}
+	m.Viewport.SetContent(m.renderContent(width))
+}
+
+func (m StashDetailModel) Update(msg tea.Msg) (StashDetailModel, tea.Cmd) {
+	var cmd tea.Cmd
+	m.Viewport, cmd = m.Viewport.Update(msg)
+	return m, cmd
+}
+
+func (m StashDetailModel) renderContent(width int) string {
+	if m.Detail == nil {
+		return StyleDim.Render("   No stash selected")
+	}
+
+	var s strings.Builder
+
+	s.WriteString(fmt.Sprintf(" %s %s\n", StyleSelected.Render(fmt.Sprintf("stash@{%d}", m.Entry.ID)), StyleNormal.Render(m.Entry.Message)))
+	s.WriteString(StyleDim.Render(fmt.Sprintf(" Branch: %s • Base: %s • %s", m.Entry.Branch, m.Detail.Base[:7], humanize.Time(m.Entry.Timestamp))))
+	s.WriteString("\n\n")
+
+	s.WriteString(renderStashFiles("Staged", m.Detail.Staged, ColorSuccess))
+	s.WriteString(renderStashFiles("Unstaged", m.Detail.Unstaged, ColorWarning))
+	s.WriteString(renderStashFiles("Untracked", m.Detail.Untracked, ColorError))
+
+	s.WriteString(StyleHeader.Render("Diff against base"))
+	s.WriteString("\n")
+	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(strings.Repeat("─", width)))
+	s.WriteString("\n")
+	diffLines, _, _ := renderDiff(m.Diff, width, true)
+	s.WriteString(strings.Join(diffLines, "\n"))
+
+	return s.String()
+}
+
+func renderStashFiles(title string, files []git.FileChange, color lipgloss.Color) string {
+	if len(files) == 0 {
+		return ""
+	}
+
+	var s strings.Builder
+	s.WriteString(StyleHeader.Render(fmt.Sprintf("%s (%d)", title, len(files))))
+	s.WriteString("\n")
+	for _, f := range files {
+		path := f.Path
+		if f.OldPath != "" {
+			path = f.OldPath + " → " + f.Path
+		}
+		s.WriteString(fmt.Sprintf("   %s %s\n", lipgloss.NewStyle().Foreground(color).Render(f.Status), path))
+	}
+	s.WriteString("\n")
+	return s.String()
+}
+
+func (m StashDetailModel) View() string {
+	var s strings.Builder
+
+	s.WriteString(StyleTitle.Render("Stash Inspection"))
+	s.WriteString("\n")
+	s.WriteString(m.Viewport.View())
+	s.WriteString(StyleDim.Render(fmt.Sprintf("\n '↑/↓' scroll, 'PgUp/PgDn' page, 'Esc' back • %3.f%%", m.Viewport.ScrollPercent()*100)))
+
+	return s.String()
+}
@@ -39,6 +39,32 @@ var (
 	StyleNormal = lipgloss.NewStyle().
 			Foreground(ColorText)
 
+	// Header badge for a merge, rebase etc. in progress
+	StyleOperation = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#000000")).
+			Background(ColorWarning).
+			Padding(0, 1)
+
 	StyleDim = lipgloss.NewStyle().
 			Foreground(ColorSubText)
+
+	// Diff styles
+	StyleDiffAdd = lipgloss.NewStyle().
+			Foreground(ColorSuccess)
+
+	StyleDiffDel = lipgloss.NewStyle().
+			Foreground(ColorError)
+
+	StyleDiffHunk = lipgloss.NewStyle().
+			Foreground(ColorInfo)
+
+	// Changed words inside a modified line
+	StyleDiffAddWord = lipgloss.NewStyle().
+				Foreground(ColorSecondary).
+				Background(lipgloss.Color("22"))
+
+	StyleDiffDelWord = lipgloss.NewStyle().
+				Foreground(ColorSecondary).
+				Background(lipgloss.Color("52"))
 )
@@ -0,0 +1,198 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/dustin/go-humanize"
+	"github.com/sh9336/gitdash/internal/git"
+)
+
+// tagRows is how many tags the panel shows at once around the cursor
+const tagRows = 10
+
+type TagsModel struct {
+	Tags     []git.Tag
+	Sort     git.TagSort
+	Selected int
+	Active   bool // Whether this panel is currently active/focused
+}
+
+func NewTagsModel(tags []git.Tag, order git.TagSort) TagsModel {
+	return TagsModel{
+		Tags: tags,
+		Sort: order,
+	}
+}
+
+func (m *TagsModel) Next() {
+	if m.Selected < len(m.Tags)-1 {
+		m.Selected++
+	}
+}
+
+func (m *TagsModel) Previous() {
+	if m.Selected > 0 {
+		m.Selected--
+	}
+}
+
+// SelectedTag returns the highlighted tag, if any
+func (m TagsModel) SelectedTag() (git.Tag, bool) {
+	if m.Selected < 0 || m.Selected >= len(m.Tags) {
+		return git.Tag{}, false
+	}
+	return m.Tags[m.Selected], true
+}
+
+// SetSort re-sorts the tags, keeping the cursor on the same tag
+func (m *TagsModel) SetSort(order git.TagSort) {
+	selected, ok := m.SelectedTag()
+	m.Sort = order
+	git.SortTags(m.Tags, order)
+	if ok {
+		m.selectTag(selected.Name)
+	}
+}
+
+func (m *TagsModel) selectTag(name string) {
+	for i, t := range m.Tags {
+		if t.Name == name {
+			m.Selected = i
+			return
+		}
+	}
+}
+
+// tagRefName is what the dashboard inspects for a tag; the full ref name
+// keeps a tag from being shadowed by a branch of the same name
+func tagRefName(t git.Tag) string {
+	return "refs/tags/" + t.Name
+}
+
+// inspectLabel renders an inspected revision for the header
+func inspectLabel(name string) string {
+	if tag, ok := strings.CutPrefix(name, "refs/tags/"); ok {
+		return "tag " + tag
+	}
+	if len(name) == 40 && strings.Trim(name, "0123456789abcdef") == "" {
+		return name[:7]
+	}
+	return name
+}
+
+// handleTagsKey runs the Tags panel bindings; ok is false for keys it does not own
+func (m Model) handleTagsKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.TagsModel.Previous()
+		return m.inspectSelectedTag()
+	case "down", "j":
+		m.TagsModel.Next()
+		return m.inspectSelectedTag()
+	case "enter":
+		return m.inspectSelectedTag()
+	case "s":
+		order := git.TagSortDate
+		if m.TagsModel.Sort == git.TagSortDate {
+			order = git.TagSortSemver
+		}
+		m.TagsModel.SetSort(order)
+		m.StatusMessage = "Tags sorted by " + order.String()
+		return m, nil, true
+	}
+	return m, nil, false
+}
+
+// inspectSelectedTag points the commit and stats panels at the highlighted tag
+func (m Model) inspectSelectedTag() (Model, tea.Cmd, bool) {
+	t, ok := m.TagsModel.SelectedTag()
+	if !ok || tagRefName(t) == m.InspectedBranch {
+		return m, nil, true
+	}
+	m.InspectedBranch = tagRefName(t)
+	return m, refreshData(m.RepoInfo, m.Config, m.InspectedBranch, true), true
+}
+
+func (m TagsModel) View(width int) string {
+	var s strings.Builder
+
+	// Header
+	title := fmt.Sprintf("Tags (%d)", len(m.Tags))
+	if m.Active {
+		s.WriteString(StyleSelected.Copy().Bold(true).Render("★ " + title))
+	} else {
+		s.WriteString(StyleHeader.Render(title))
+	}
+	s.WriteString(StyleDim.Render("  by " + m.Sort.String()))
+	s.WriteString("\n")
+
+	if len(m.Tags) == 0 {
+		s.WriteString(StyleDim.Render("   No tags"))
+		return m.panelStyle(width).Render(s.String())
+	}
+
+	// Scroll a fixed window so long release histories stay compact
+	start := 0
+	if m.Selected >= tagRows {
+		start = m.Selected - tagRows + 1
+	}
+	end := min(start+tagRows, len(m.Tags))
+	if start > 0 {
+		s.WriteString(StyleDim.Render(fmt.Sprintf("   ↑ %d more", start)) + "\n")
+	}
+
+	for i := start; i < end; i++ {
+		t := m.Tags[i]
+		selected := m.Active && i == m.Selected
+
+		cursor := "  "
+		name := StyleNormal.Render(t.Name)
+		if selected {
+			cursor = " ▶"
+			name = StyleSelected.Copy().Underline(true).Render(t.Name)
+		}
+
+		kind := StyleDim.Render("lightweight")
+		if t.Annotated {
+			kind = lipgloss.NewStyle().Foreground(ColorInfo).Render("annotated")
+		}
+
+		line := fmt.Sprintf("%s %s %s %s %s",
+			cursor,
+			name,
+			StyleDim.Render(t.Hash[:7]),
+			kind,
+			StyleDim.Render("("+humanize.Time(t.When)+")"),
+		)
+		s.WriteString(line + "\n")
+
+		// The highlighted annotated tag also shows who tagged it and why
+		if selected && t.Annotated {
+			s.WriteString(StyleDim.Render(fmt.Sprintf("       %s <%s>, %s", t.Tagger, t.TaggerEmail, t.When.Format("2006-01-02 15:04"))) + "\n")
+			lines := strings.Split(t.Message, "\n")
+			if len(lines) > 4 {
+				lines = append(lines[:4], "…")
+			}
+			for _, l := range lines {
+				s.WriteString("       " + StyleNormal.Render(l) + "\n")
+			}
+		}
+	}
+
+	if end < len(m.Tags) {
+		s.WriteString(StyleDim.Render(fmt.Sprintf("   ↓ %d more", len(m.Tags)-end)) + "\n")
+	}
+
+	return m.panelStyle(width).Render(s.String())
+}
+
+func (m TagsModel) panelStyle(width int) lipgloss.Style {
+	style := StylePanel.Copy().Width(width)
+	if m.Active {
+		style = style.BorderForeground(ColorPrimary)
+	}
+	return style
+}
@@ -2,92 +2,313 @@ package ui
 
 import (
 	"fmt"
+	"path"
 	"sort"
 	"strings"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/sh9336/gitdash/internal/git"
 )
 
 type WorkDirModel struct {
-	Status *git.WorkingDirStatus
+	Status   *git.WorkingDirStatus
+	Files    []git.FileStatus // Status.Files in display order
+	Selected int
+	Active   bool // Whether this panel is currently active/focused
 }
 
 func NewWorkDirModel(status *git.WorkingDirStatus) WorkDirModel {
-	return WorkDirModel{
+	m := WorkDirModel{
 		Status: status,
 	}
+	if status == nil {
+		return m
+	}
+
+	// Group like `git status`, then sort by name
+	m.Files = make([]git.FileStatus, len(status.Files))
+	copy(m.Files, status.Files)
+	sort.SliceStable(m.Files, func(i, j int) bool {
+		gi, gj := fileGroup(m.Files[i]), fileGroup(m.Files[j])
+		if gi != gj {
+			return gi < gj
+		}
+		return m.Files[i].Path < m.Files[j].Path
+	})
+	return m
+}
+
+// File groups in display order
+const (
+	groupConflicted = iota
+	groupStaged
+	groupUnstaged
+	groupUntracked
+)
+
+func fileGroup(f git.FileStatus) int {
+	switch {
+	case f.Status == "U":
+		return groupConflicted
+	case f.Staged:
+		return groupStaged
+	case f.Status == "?":
+		return groupUntracked
+	}
+	return groupUnstaged
+}
+
+// workDirMsg replaces the working directory status after staging
+type workDirMsg struct {
+	Status  *git.WorkingDirStatus
+	Message string
+}
+
+// stageCmd stages or unstages paths (files or directories, none for all)
+// and reloads the status right away
+func stageCmd(repoPath string, renames git.RenameOptions, stage bool, message string, paths ...string) tea.Cmd {
+	return func() tea.Msg {
+		r, err := git.OpenRepo(repoPath)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		if stage {
+			err = git.StagePaths(r, paths...)
+		} else {
+			err = git.UnstagePaths(r, paths...)
+		}
+		if err != nil {
+			return errMsg(err)
+		}
+
+		status, err := git.GetWorkingDirStatus(r, renames)
+		if err != nil {
+			return errMsg(err)
+		}
+
+		return workDirMsg{Status: status, Message: message}
+	}
+}
+
+func (m *WorkDirModel) Next() {
+	if m.Selected < len(m.Files)-1 {
+		m.Selected++
+	}
+}
+
+func (m *WorkDirModel) Previous() {
+	if m.Selected > 0 {
+		m.Selected--
+	}
+}
+
+// SelectedFile returns the highlighted file, if any
+func (m WorkDirModel) SelectedFile() (git.FileStatus, bool) {
+	if m.Selected < 0 || m.Selected >= len(m.Files) {
+		return git.FileStatus{}, false
+	}
+	return m.Files[m.Selected], true
+}
+
+// handleWorkDirKey runs the Working Directory panel bindings; ok is false for keys it does not own
+func (m Model) handleWorkDirKey(msg tea.KeyMsg) (Model, tea.Cmd, bool) {
+	switch msg.String() {
+	case "up", "k":
+		m.WorkDirModel.Previous()
+		return m, nil, true
+	case "down", "j":
+		m.WorkDirModel.Next()
+		return m, nil, true
+	case "h":
+		f, ok := m.WorkDirModel.SelectedFile()
+		if !ok {
+			return m, nil, true
+		}
+		if f.Status == "?" || f.Status == "A" {
+			m.StatusMessage = fmt.Sprintf("%s is not committed yet, it has no history", f.Path)
+			return m, nil, true
+		}
+		name := committedPath(f)
+		m.StatusMessage = fmt.Sprintf("Loading history of %s...", name)
+		return m, fileHistoryCmd(m.RepoInfo.Path, "HEAD", name), true
+	case " ":
+		f, ok := m.WorkDirModel.SelectedFile()
+		if !ok {
+			return m, nil, true
+		}
+		// Both sides of a rename go together, or it would split up again
+		paths := []string{f.Path}
+		if f.Status == "R" {
+			paths = append(paths, f.OldPath)
+		}
+		if f.Staged {
+			return m, stageCmd(m.RepoInfo.Path, renameOptions(m.Config), false, "Unstaged "+f.Path, paths...), true
+		}
+		return m, stageCmd(m.RepoInfo.Path, renameOptions(m.Config), true, "Staged "+f.Path, paths...), true
+	case "a":
+		// Stage everything, or unstage everything when nothing is left to stage
+		for _, f := range m.WorkDirModel.Files {
+			if !f.Staged {
+				return m, stageCmd(m.RepoInfo.Path, renameOptions(m.Config), true, "Staged all changes"), true
+			}
+		}
+		return m, stageCmd(m.RepoInfo.Path, renameOptions(m.Config), false, "Unstaged all changes"), true
+	case "d":
+		f, ok := m.WorkDirModel.SelectedFile()
+		if !ok {
+			return m, nil, true
+		}
+		dir := path.Dir(f.Path)
+		label := dir + "/"
+		if dir == "." {
+			label = "the repository root"
+		}
+		if f.Staged {
+			return m, stageCmd(m.RepoInfo.Path, renameOptions(m.Config), false, "Unstaged "+label, dir), true
+		}
+		return m, stageCmd(m.RepoInfo.Path, renameOptions(m.Config), true, "Staged "+label, dir), true
+	case "enter":
+		f, ok := m.WorkDirModel.SelectedFile()
+		if !ok {
+			return m, nil, true
+		}
+		if f.Status == "U" {
+			m.StatusMessage = fmt.Sprintf("Loading the versions of %s...", f.Path)
+			return m, conflictCmd(m.RepoInfo.Path, f.Path), true
+		}
+		m.StatusMessage = fmt.Sprintf("Loading hunks of %s...", f.Path)
+		return m, fileHunksCmd(m.RepoInfo.Path, f.Path), true
+	case "c":
+		m.StatusMessage = "Loading staged changes..."
+		return m, composerCmd(m.RepoInfo.Path, renameOptions(m.Config)), true
+	case "B":
+		f, ok := m.WorkDirModel.SelectedFile()
+		if !ok {
+			return m, nil, true
+		}
+		name := committedPath(f)
+		m.StatusMessage = fmt.Sprintf("Blaming %s at %s...", name, inspectLabel(m.InspectedBranch))
+		return m, blameCmd(m.RepoInfo.Path, m.InspectedBranch, name), true
+	}
+	return m, nil, false
+}
+
+// committedPath is the name a file has in the commits, its old one when
+// it was renamed or copied since
+func committedPath(f git.FileStatus) string {
+	if f.OldPath != "" {
+		return f.OldPath
+	}
+	return f.Path
+}
+
+// renameLabel renders a rename or copy as "old → new (94%)"
+func renameLabel(f git.FileStatus, name string) string {
+	return StyleDim.Render(f.OldPath+" → ") + name + StyleDim.Render(fmt.Sprintf(" (%d%%)", f.Similarity))
+}
+
+// renameCounts counts the renames and copies shown in a group, which the
+// group's own count leaves out
+func (m WorkDirModel) renameCounts(group int) string {
+	renamed, copied := 0, 0
+	for _, f := range m.Files {
+		if fileGroup(f) != group {
+			continue
+		}
+		switch f.Status {
+		case "R":
+			renamed++
+		case "C":
+			copied++
+		}
+	}
+	var parts []string
+	if renamed > 0 {
+		parts = append(parts, fmt.Sprintf("renamed: %d", renamed))
+	}
+	if copied > 0 {
+		parts = append(parts, fmt.Sprintf("copied: %d", copied))
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return StyleDim.Render(" • " + strings.Join(parts, ", "))
 }
 
 func (m WorkDirModel) View(width int) string {
 	var s strings.Builder
 
 	// Header
-	s.WriteString(StyleHeader.Render("Working Directory"))
+	if m.Active {
+		s.WriteString(StyleSelected.Copy().Bold(true).Render("★ Working Directory"))
+	} else {
+		s.WriteString(StyleHeader.Render("Working Directory"))
+	}
+	if m.Status == nil {
+		s.WriteString("\n")
+		s.WriteString(StyleDim.Render("   Error loading status"))
+		return m.panelStyle(width).Render(s.String())
+	}
 	s.WriteString(StyleDim.Render(fmt.Sprintf(" (On branch: %s)", m.Status.BranchName)))
 	s.WriteString("\n")
 	s.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(strings.Repeat("─", width)))
 	s.WriteString("\n")
 
-	if m.Status == nil {
-		s.WriteString(StyleDim.Render("   Error loading status"))
-		return StylePanel.Copy().Width(width).Render(s.String())
-	}
-
 	if len(m.Status.Files) == 0 {
 		s.WriteString(StyleDim.Render("   Working directory clean"))
-		return StylePanel.Copy().Width(width).Render(s.String())
+		return m.panelStyle(width).Render(s.String())
 	}
 
-	// Summaries
-	if m.Status.Modified > 0 {
-		s.WriteString(fmt.Sprintf(" %s Modified: %d\n", lipgloss.NewStyle().Foreground(ColorWarning).Render("●"), m.Status.Modified))
+	headings := map[int]string{
+		groupConflicted: fmt.Sprintf("%s Conflicts: %d", lipgloss.NewStyle().Foreground(ColorError).Render("✗"), m.Status.Conflicted),
+		groupStaged:     fmt.Sprintf("%s Staged: %d", lipgloss.NewStyle().Foreground(ColorSuccess).Render("✓"), m.Status.Staged),
+		groupUnstaged:   fmt.Sprintf("%s Modified: %d", lipgloss.NewStyle().Foreground(ColorWarning).Render("●"), m.Status.Modified),
+		groupUntracked:  fmt.Sprintf("%s Untracked: %d", lipgloss.NewStyle().Foreground(ColorError).Render("?"), m.Status.Untracked),
 	}
-	if m.Status.Staged > 0 {
-		s.WriteString(fmt.Sprintf(" %s Staged: %d\n", lipgloss.NewStyle().Foreground(ColorSuccess).Render("✓"), m.Status.Staged))
-	}
-	if m.Status.Untracked > 0 {
-		s.WriteString(fmt.Sprintf(" %s Untracked: %d\n", lipgloss.NewStyle().Foreground(ColorError).Render("?"), m.Status.Untracked))
-	}
-
-	s.WriteString("\n")
-
-	// List files (Top 10 maybe?)
-	// Sort by status then name
-	sortedFiles := make([]git.FileStatus, len(m.Status.Files))
-	copy(sortedFiles, m.Status.Files)
-
-	sort.Slice(sortedFiles, func(i, j int) bool {
-		if sortedFiles[i].Status != sortedFiles[j].Status {
-			return sortedFiles[i].Status < sortedFiles[j].Status
-		}
-		return sortedFiles[i].Path < sortedFiles[j].Path
-	})
 
-	count := 0
-	for _, f := range sortedFiles {
+	group := -1
+	for i, f := range m.Files {
 		// No limit on files shown
-		icon := " "
-		color := StyleNormal
+		if g := fileGroup(f); g != group {
+			if group != -1 {
+				s.WriteString("\n")
+			}
+			group = g
+			s.WriteString(" " + headings[g] + m.renameCounts(g) + "\n")
+		}
 
-		switch f.Status {
-		case "M":
-			icon = "●"
-			color = lipgloss.NewStyle().Foreground(ColorWarning)
-		case "?":
-			icon = "?"
+		color := lipgloss.NewStyle().Foreground(ColorWarning)
+		switch group {
+		case groupStaged:
+			color = lipgloss.NewStyle().Foreground(ColorSuccess)
+		case groupConflicted, groupUntracked:
 			color = lipgloss.NewStyle().Foreground(ColorError)
-		default:
-			// check if staged
-			if f.Staged {
-				icon = "✓"
-				color = lipgloss.NewStyle().Foreground(ColorSuccess)
-			}
 		}
 
-		s.WriteString(fmt.Sprintf(" %s %s\n", color.Render(icon), f.Path))
-		count++
+		cursor := " "
+		name := f.Path
+		if m.Active && i == m.Selected {
+			cursor = "▶"
+			name = StyleSelected.Copy().Underline(true).Render(f.Path)
+		}
+		if f.Conflict != "" {
+			name += StyleDim.Render(" (" + f.Conflict + ")")
+		}
+		if f.OldPath != "" {
+			name = renameLabel(f, name)
+		}
+		s.WriteString(fmt.Sprintf("%s  %s %s\n", cursor, color.Render(f.Status), name))
 	}
 
-	return StylePanel.Copy().Width(width).Render(s.String())
+	return m.panelStyle(width).Render(s.String())
+}
+
+func (m WorkDirModel) panelStyle(width int) lipgloss.Style {
+	style := StylePanel.Copy().Width(width)
+	if m.Active {
+		style = style.BorderForeground(ColorPrimary)
+	}
+	return style
 }